@@ -19,15 +19,32 @@ func init() {
 }
 
 func TestAllSpecs(t *testing.T) {
+	nanospec.Run(t, AssertAdapterSpec)
 	nanospec.Run(t, ConcurrencySpec)
 	nanospec.Run(t, ContextSpec)
+	nanospec.Run(t, DiffSpec)
+	nanospec.Run(t, DotSpec)
 	nanospec.Run(t, ExecutionModelSpec)
 	nanospec.Run(t, ExpectationsSpec)
+	nanospec.Run(t, FormatterSpec)
 	nanospec.Run(t, FuncNameSpec)
+	nanospec.Run(t, GitHubActionsSpec)
+	nanospec.Run(t, GoroutineLeakSpec)
+	nanospec.Run(t, HooksSpec)
+	nanospec.Run(t, JSONReportSpec)
+	nanospec.Run(t, JUnitSpec)
 	nanospec.Run(t, LocationSpec)
 	nanospec.Run(t, MatcherMessagesSpec)
 	nanospec.Run(t, MatchersSpec)
+	nanospec.Run(t, ParallelSpec)
 	nanospec.Run(t, PrinterSpec)
 	nanospec.Run(t, RecoverSpec)
+	nanospec.Run(t, RegistrySpec)
+	nanospec.Run(t, ReporterSpec)
 	nanospec.Run(t, ResultsSpec)
+	nanospec.Run(t, SourceSpec)
+	nanospec.Run(t, TAPSpec)
+	nanospec.Run(t, TeamCitySpec)
+	nanospec.Run(t, TimeoutSpec)
+	nanospec.Run(t, TypedMatchersSpec)
 }
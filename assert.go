@@ -0,0 +1,61 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"strings"
+	"testing"
+)
+
+// AssertAdapter makes it possible to use the matchers in ordinary Go tests,
+// without writing specs. The failures are reported with t.Errorf. Example:
+//    func TestAnswer(t *testing.T) {
+//        a := gospec.NewAssertAdapter(t)
+//        a.Expect(theAnswer(), gospec.Equals, 42)
+//    }
+type AssertAdapter struct {
+	t testing.TB
+}
+
+func NewAssertAdapter(t testing.TB) *AssertAdapter {
+	return &AssertAdapter{t}
+}
+
+// Makes an expectation, the same way as Context.Expect. The test
+// continues after a failure.
+func (this *AssertAdapter) Expect(actual interface{}, matcher Matcher, expected ...interface{}) {
+	this.t.Helper()
+	this.report(callerLocation(), ExpectFailed, actual, matcher, expected...)
+}
+
+// Makes an assumption, the same way as Context.Assume. The test
+// is stopped with t.FailNow after a failure.
+func (this *AssertAdapter) Assume(actual interface{}, matcher Matcher, expected ...interface{}) {
+	this.t.Helper()
+	if this.report(callerLocation(), AssumeFailed, actual, matcher, expected...) {
+		this.t.FailNow()
+	}
+}
+
+// The errors are collected and reported here, instead of calling t.Errorf
+// from an errorLogger, so that t.Helper makes the test report the line
+// where the expectation was made. Returns true if there were errors.
+func (this *AssertAdapter) report(location *Location, matcherType ErrorType, actual interface{}, matcher Matcher, expected ...interface{}) bool {
+	this.t.Helper()
+	buffer := newErrorBuffer()
+	m := newMatcherAdapter(toStackTrace(location), buffer, matcherType)
+	m.Expect(actual, matcher, expected...)
+	for _, error := range buffer.errors {
+		this.t.Errorf("%v", strings.TrimSuffix(formatErrorMessage(error), "\n"))
+	}
+	return len(buffer.errors) > 0
+}
+
+func toStackTrace(loc *Location) []*Location {
+	if loc != nil {
+		return []*Location{loc}
+	}
+	return []*Location{}
+}
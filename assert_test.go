@@ -0,0 +1,71 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"testing"
+)
+
+func AssertAdapterSpec(c nanospec.Context) {
+	t := new(spyT)
+	a := NewAssertAdapter(t)
+
+	c.Specify("Passing expectations are not reported", func() {
+		a.Expect(1, Equals, 1)
+		c.Expect(len(t.errors)).Equals(0)
+	})
+	c.Specify("Failed expectations are reported with t.Errorf", func() {
+		a.Expect(1, Equals, 2)
+		c.Expect(len(t.errors)).Equals(1)
+		c.Expect(t.errors[0]).Equals("*** Expected: equals “2”\n         got: “1”")
+		c.Expect(t.failedNow).IsFalse()
+	})
+	c.Specify("Errors of matchers are reported with t.Errorf", func() {
+		a.Expect("foo", Contains, 1)
+		c.Expect(len(t.errors)).Equals(1)
+	})
+	c.Specify("Failed assumptions stop the test", func() {
+		a.Assume(1, Equals, 2)
+		c.Expect(t.errors[0]).Equals("*** Assumed: equals “2”\n        got: “1”")
+		c.Expect(t.failedNow).IsTrue()
+	})
+	c.Specify("Passing assumptions do not stop the test", func() {
+		a.Assume(1, Equals, 1)
+		c.Expect(t.failedNow).IsFalse()
+	})
+	c.Specify("The helpers are marked, so that the test reports the line of the expectation", func() {
+		a.Expect(1, Equals, 2)
+		c.Expect(t.helpers > 0).IsTrue()
+	})
+}
+
+func TestAssertAdapter(t *testing.T) {
+	a := NewAssertAdapter(t)
+	a.Expect(42, Equals, 42)
+	a.Assume([]int{1, 2}, Contains, 2)
+}
+
+// Records the calls which AssertAdapter makes. The embedded testing.TB
+// is nil, so calling any other method panics.
+type spyT struct {
+	testing.TB
+	errors    []string
+	failedNow bool
+	helpers   int
+}
+
+func (this *spyT) Errorf(format string, args ...interface{}) {
+	this.errors = append(this.errors, fmt.Sprintf(format, args...))
+}
+
+func (this *spyT) FailNow() {
+	this.failedNow = true
+}
+
+func (this *spyT) Helper() {
+	this.helpers++
+}
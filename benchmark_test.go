@@ -46,6 +46,52 @@ func BenchmarkReport(b *testing.B) {
 	}
 }
 
+func BenchmarkExpect(b *testing.B) {
+	b.ReportAllocs()
+	runSpec(func(c Context) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			c.Expect(1, Equals, 1)
+		}
+		b.StopTimer()
+	})
+}
+
+// The results of the benchmarked functions are stored in package-level
+// variables, so that the compiler cannot optimize the calls away.
+var benchmarkError error
+var benchmarkArray []interface{}
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkError = Errorf("is %v and %v", i, i)
+	}
+}
+
+func BenchmarkErrorf2(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkError = errorf2("is %v and %v", i, i)
+	}
+}
+
+func BenchmarkToArray(b *testing.B) {
+	b.ReportAllocs()
+	values := make([]int, 1000)
+	for i := 0; i < b.N; i++ {
+		benchmarkArray, _ = toArray(values)
+	}
+}
+
+func BenchmarkToArrayOfInterfaces(b *testing.B) {
+	b.ReportAllocs()
+	values := make([]interface{}, 1000)
+	for i := 0; i < b.N; i++ {
+		benchmarkArray, _ = toArray(values)
+	}
+}
+
 func runSpecs() *Runner {
 	runner := NewRunner()
 	for i := 0; i < ROOT_SPEC_COUNT; i++ {
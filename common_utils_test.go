@@ -4,6 +4,14 @@
 
 package gospec
 
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
 func runSpec(spec func(Context)) *ResultCollector {
 	r := NewRunner()
 	r.AddNamedSpec("RootSpec", spec)
@@ -29,66 +37,147 @@ func countSpecNames(specs []*specRun) map[string]int {
 	return results
 }
 
+// Reports the spec as passing or failing, depending on whether it has errors.
+func reportSpec(reporter Reporter, nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	reporter.SpecStarted(nestingLevel, name)
+	if len(errors) == 0 {
+		reporter.SpecPassed(nestingLevel, name, duration)
+	} else {
+		reporter.SpecFailed(nestingLevel, name, errors, duration)
+	}
+}
+
+// Executes the root specs "A" and "B" with the reporter given to
+// Runner.ReportTo. Returns the output which was written while "B" was still
+// being executed, as soon as it satisfies the condition, and the whole output
+// after Run has returned.
+func reportWhileRunning(newReporter func(out io.Writer) Reporter, ready func(output string) bool) (whileRunning string, afterRun string) {
+	out := new(syncBuffer)
+	release := make(chan bool)
+	r := NewRunner()
+	r.AddNamedSpec("A", func(c Context) {
+		c.Specify("Child", func() {})
+	})
+	r.AddNamedSpec("B", func(c Context) {
+		<-release
+	})
+	r.ReportTo(newReporter(out))
+	done := make(chan bool)
+	go func() {
+		r.Run()
+		done <- true
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !ready(out.String()) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	whileRunning = out.String()
+	close(release)
+	<-done
+	return whileRunning, out.String()
+}
+
+// Buffer which the spec can read while a Runner is writing to it.
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (this *syncBuffer) Write(p []byte) (int, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.buffer.Write(p)
+}
+
+func (this *syncBuffer) String() string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.buffer.String()
+}
+
+// Sets the environment variable for the duration of the closure.
+func withEnv(name string, value string, closure func()) {
+	original, wasSet := os.LookupEnv(name)
+	defer func() {
+		if wasSet {
+			os.Setenv(name, original)
+		} else {
+			os.Unsetenv(name)
+		}
+	}()
+	os.Setenv(name, value)
+	closure()
+}
+
 // Test dummies
 
 var testSpy = ""
+var testSpyMutex sync.Mutex
 
 func resetTestSpy() {
 	testSpy = ""
 }
 
+// The dummy specs may be executed concurrently by a Runner.
+func spy(event string) {
+	testSpyMutex.Lock()
+	defer testSpyMutex.Unlock()
+	testSpy += event
+}
+
 func DummySpecWithNoChildren(c Context) {
-	testSpy += "root"
+	spy("root")
 }
 
 func DummySpecWithOneChild(c Context) {
-	testSpy += "root"
+	spy("root")
 	c.Specify("Child A", func() {
-		testSpy += ",a"
+		spy(",a")
 	})
 }
 
 func DummySpecWithTwoChildren(c Context) {
-	testSpy += "root"
+	spy("root")
 	c.Specify("Child A", func() {
-		testSpy += ",a"
+		spy(",a")
 	})
 	c.Specify("Child B", func() {
-		testSpy += ",b"
+		spy(",b")
 	})
 }
 
 func DummySpecWithNestedChildren(c Context) {
-	testSpy += "root"
+	spy("root")
 	c.Specify("Child A", func() {
-		testSpy += ",a"
+		spy(",a")
 		c.Specify("Child AA", func() {
-			testSpy += ",aa"
+			spy(",aa")
 		})
 	})
 }
 
 func DummySpecWithMultipleNestedChildren(c Context) {
-	testSpy += "root"
+	spy("root")
 	c.Specify("Child A", func() {
-		testSpy += ",a"
+		spy(",a")
 		c.Specify("Child AA", func() {
-			testSpy += ",aa"
+			spy(",aa")
 		})
 		c.Specify("Child AB", func() {
-			testSpy += ",ab"
+			spy(",ab")
 		})
 	})
 	c.Specify("Child B", func() {
-		testSpy += ",b"
+		spy(",b")
 		c.Specify("Child BA", func() {
-			testSpy += ",ba"
+			spy(",ba")
 		})
 		c.Specify("Child BB", func() {
-			testSpy += ",bb"
+			spy(",bb")
 		})
 		c.Specify("Child BC", func() {
-			testSpy += ",bc"
+			spy(",bc")
 		})
 	})
 }
@@ -5,8 +5,11 @@
 package gospec
 
 import (
+	"bytes"
 	"math"
+	"runtime"
 	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"sync"
 	"time"
 )
 
@@ -58,3 +61,263 @@ func VerySlowDummySpec(c Context) {
 		})
 	})
 }
+
+// Executing specs with a limited number of workers
+
+func ParallelSpec(c nanospec.Context) {
+
+	c.Specify("Parallel execution gives the same results as sequential execution", func() {
+		report := func(run func(r *Runner)) string {
+			r := NewRunner()
+			r.AddSpec(DummySpecWithFailures)
+			run(r)
+			out := new(bytes.Buffer)
+			printer := NewPrinter(SimplePrintFormat(out))
+			printer.ShowAll()
+			r.Results().Report(printer)
+			return out.String()
+		}
+		sequential := report(func(r *Runner) { r.RunParallel(1) })
+
+		c.Expect(report(func(r *Runner) { r.RunParallel(4) })).Equals(sequential)
+		c.Expect(report(func(r *Runner) { r.Run() })).Equals(sequential)
+	})
+
+	c.Specify("At most the given number of specs are executed at the same time", func() {
+		counter := new(concurrencyCounter)
+		r := NewRunner()
+		r.AddNamedSpec("Slow", counter.SlowSpec(false))
+		r.RunParallel(2)
+
+		c.Expect(r.Results().TotalCount()).Equals(7)
+		c.Expect(counter.max <= 2).IsTrue()
+	})
+
+	c.Specify("Serial specs are not executed at the same time as other specs", func() {
+		counter := new(concurrencyCounter)
+		r := NewRunner()
+		r.AddNamedSpec("Slow 1", counter.SlowSpec(false))
+		r.AddNamedSpec("Slow 2", counter.SlowSpec(true), Serial())
+		r.AddNamedSpec("Slow 3", counter.SlowSpec(false))
+		r.RunParallel(3)
+
+		c.Expect(r.Results().TotalCount()).Equals(21)
+		c.Expect(counter.max <= 3).IsTrue()
+		c.Expect(counter.maxWithSerial).Equals(1)
+	})
+
+	c.Specify("Expectations can be made from many goroutines at the same time", func() {
+		results := runSpec(func(c Context) {
+			var wg sync.WaitGroup
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					c.Expect(i, Equals, -1)
+				}(i)
+			}
+			wg.Wait()
+		})
+		reporter := new(argumentsReporter)
+		results.Report(reporter)
+		c.Expect(len(reporter.errors)).Equals(100)
+	})
+
+	c.Specify("Expectations can be made from many goroutines inside AssertAll", func() {
+		results := runSpec(func(c Context) {
+			c.AssertAll(func() {
+				var wg sync.WaitGroup
+				for i := 0; i < 100; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						c.Assume(i, Equals, -1)
+					}(i)
+				}
+				wg.Wait()
+			})
+		})
+		reporter := new(argumentsReporter)
+		results.Report(reporter)
+		c.Expect(len(reporter.errors)).Equals(100)
+	})
+}
+
+func DummySpecWithFailures(c Context) {
+	c.Specify("Child A", func() {
+		c.Expect(1, Equals, 2)
+		c.Specify("Child AA", func() {})
+	})
+	c.Specify("Child B", func() {
+		c.Assume(1, Equals, 2)
+		c.Specify("Child BA", func() {})
+	})
+	c.Specify("Child C", func() {
+		c.Specify("Child CA", func() {})
+		c.Specify("Child CB", func() {
+			c.Expect(3, Equals, 4)
+		})
+	})
+}
+
+// Keeps track of how many specs are executing at the same time.
+type concurrencyCounter struct {
+	mutex         sync.Mutex
+	running       int
+	runningSerial int
+	max           int
+	maxWithSerial int
+}
+
+func (this *concurrencyCounter) SlowSpec(serial bool) func(Context) {
+	return func(c Context) {
+		for _, name := range []string{"A", "B", "C", "D", "E", "F"} {
+			c.Specify(name, func() {
+				this.enter(serial)
+				time.Sleep(DELAY / 10)
+				this.exit(serial)
+			})
+		}
+	}
+}
+
+func (this *concurrencyCounter) enter(serial bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.running++
+	if serial {
+		this.runningSerial++
+	}
+	if this.running > this.max {
+		this.max = this.running
+	}
+	if this.runningSerial > 0 && this.running > this.maxWithSerial {
+		this.maxWithSerial = this.running
+	}
+}
+
+func (this *concurrencyCounter) exit(serial bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.running--
+	if serial {
+		this.runningSerial--
+	}
+}
+
+// Failing specs which take too long to execute
+
+func TimeoutSpec(c nanospec.Context) {
+	fast := func(c Context) {}
+	slow := func(c Context) {
+		time.Sleep(DELAY)
+	}
+	runWithTimeout := func(runner func(r *Runner)) *ResultCollector {
+		r := NewRunner()
+		runner(r)
+		r.Run()
+		return r.Results()
+	}
+	failureOf := func(results *ResultCollector) string {
+		reporter := new(argumentsReporter)
+		results.Report(reporter)
+		if len(reporter.errors) == 0 {
+			return ""
+		}
+		return reporter.errors[0].Message
+	}
+
+	c.Specify("A spec which takes longer than its timeout fails", func() {
+		start := time.Now()
+		results := runWithTimeout(func(r *Runner) {
+			r.AddNamedSpec("should process large file", slow, WithTimeout(DELAY/5))
+		})
+		c.Expect(time.Since(start) < DELAY).IsTrue()
+		c.Expect(results.FailCount()).Equals(1)
+		c.Expect(failureOf(results)).Equals("Spec 'should process large file' timed out after 10ms.")
+	})
+
+	c.Specify("A spec which is faster than its timeout passes", func() {
+		results := runWithTimeout(func(r *Runner) {
+			r.AddNamedSpec("Fast", fast, WithTimeout(DELAY))
+		})
+		c.Expect(results.PassCount()).Equals(1)
+	})
+
+	c.Specify("The default timeout applies to specs without their own timeout", func() {
+		results := runWithTimeout(func(r *Runner) {
+			r.SetDefaultTimeout(DELAY / 5)
+			r.AddNamedSpec("Slow", slow)
+			r.AddNamedSpec("Slow with longer timeout", slow, WithTimeout(2*DELAY))
+		})
+		c.Expect(results.FailCount()).Equals(1)
+		c.Expect(results.PassCount()).Equals(1)
+		c.Expect(failureOf(results)).Equals("Spec 'Slow' timed out after 10ms.")
+	})
+
+	c.Specify("The goroutine of a timed out spec exits when the spec returns", func() {
+		returned := make(chan bool)
+		before := runtime.NumGoroutine()
+		runWithTimeout(func(r *Runner) {
+			r.AddNamedSpec("Slow", func(c Context) {
+				time.Sleep(DELAY / 2)
+				close(returned)
+			}, WithTimeout(DELAY/10))
+		})
+		<-returned
+		time.Sleep(DELAY / 10)
+		c.Expect(runtime.NumGoroutine() <= before).IsTrue()
+	})
+
+	c.Specify("The results of a timed out spec are ignored after the timeout", func() {
+		returned := make(chan bool)
+		results := runWithTimeout(func(r *Runner) {
+			r.AddNamedSpec("Root", func(c Context) {
+				c.Specify("Slow", func() {
+					c.Specify("Child 1", func() {
+						c.Expect(1, Equals, 1)
+					})
+					time.Sleep(DELAY / 2)
+					c.Expect(1, Equals, 2)
+					c.Specify("Child 2", func() {
+						c.Expect(1, Equals, 2)
+					})
+					close(returned)
+				})
+				c.Specify("Sibling", func() {
+					c.Expect(1, Equals, 1)
+				})
+			}, WithTimeout(DELAY/10))
+		})
+		<-returned
+
+		recorder := new(recordingReporter)
+		results.Report(recorder)
+		c.Expect(recorder.String()).Equals(
+			"started 0 Root\n" +
+				"passed 0 Root\n" +
+				"started 1 Slow\n" +
+				"failed 1 Slow 1\n" +
+				"started 2 Child 1\n" +
+				"passed 2 Child 1\n" +
+				"started 1 Sibling\n" +
+				"passed 1 Sibling\n" +
+				"finished 3 1 0\n")
+	})
+
+	c.Specify("The time spent executing the children does not count towards the timeout", func() {
+		results := runWithTimeout(func(r *Runner) {
+			r.AddNamedSpec("Root", func(c Context) {
+				c.Specify("Parent", func() {
+					c.Specify("Child", func() {
+						time.Sleep(DELAY / 10)
+					})
+					time.Sleep(DELAY / 10)
+				})
+				time.Sleep(DELAY / 10)
+			}, WithTimeout(DELAY/5))
+		})
+		c.Expect(results.FailCount()).Equals(0)
+		c.Expect(results.PassCount()).Equals(3)
+	})
+}
@@ -6,6 +6,9 @@ package gospec
 
 import (
 	"container/list"
+	"fmt"
+	"sync"
+	"time"
 )
 
 // Context controls the execution of the current spec. Child specs can be
@@ -18,15 +21,95 @@ type Context interface {
 	// specification as code.
 	Specify(name string, closure func())
 
+	// Creates a focused child spec. Otherwise the same as Specify, but when
+	// there are focused specs, only they, their children and their parents are
+	// reported and all the other specs are reported as skipped. The other specs
+	// are still executed, to find the focused specs inside them. Useful when
+	// debugging a single spec, but should not be left in the code.
+	FSpecify(name string, closure func())
+
+	// Creates a pending child spec, which is reported as pending without
+	// executing the closure. Useful for writing the names of specs before
+	// implementing them. Calling Specify with a nil closure does the same.
+	PSpecify(name string, closure func())
+
+	// Same as PSpecify.
+	XSpecify(name string, closure func())
+
+	// Registers a closure which is executed before every child spec of the
+	// currently executing spec. The hooks are executed in the order in which
+	// they were registered, so they should be registered before the children.
+	// If the hook panics, the child spec fails without being executed.
+	BeforeEach(closure func())
+
+	// Registers a closure which is executed after every child spec of the
+	// currently executing spec, also when the child spec fails. The hooks are
+	// executed in the reverse order of their registration.
+	AfterEach(closure func())
+
+	// Registers a closure which is executed only once, before the first
+	// child spec of the currently executing spec. Because the spec is
+	// executed again for each of its children, the state which is created
+	// in the hook must be stored outside the spec, for example in a
+	// package-level variable. If the hook panics, the children will fail.
+	BeforeAll(closure func())
+
+	// Registers a closure which is executed only once, after all the
+	// specs have been executed, also when some of them fail. The hooks
+	// of nested specs are executed before the hooks of their parents.
+	AfterAll(closure func())
+
+	// Registers a closure which is executed when the currently executing
+	// spec and its children have finished, also when the spec fails.
+	// Similar to defer, but can be called in a helper function which
+	// creates a resource. The closures are executed in reverse order.
+	DeferCleanup(closure func())
+
 	// Makes an expectation. For example:
 	//    c.Expect(theAnswer, Equals, 42)
 	//    c.Expect(theAnswer, Not(Equals), 666)
 	//    c.Expect(thereIsASpoon, IsFalse)
 	Expect(actual interface{}, matcher Matcher, expected ...interface{})
 
+	// Starts a fluent expectation, which reads in the same order as a sentence.
+	// Otherwise the same as Expect. For example:
+	//    c.ExpectThat(theAnswer).To(Equals, 42)
+	//    c.ExpectThat(theAnswer).NotTo(Equals, 666)
+	ExpectThat(actual interface{}) *Expectation
+
+	// Same as Expect. Makes it explicit that the spec is meant to continue
+	// after a failed expectation, so that all the failures will be reported.
+	SoftExpect(actual interface{}, matcher Matcher, expected ...interface{})
+
+	// Executes the closure and reports all its failures when it returns.
+	// Also a panic inside the closure is reported as a failure, after which
+	// the spec continues normally. For example:
+	//    c.AssertAll(func() {
+	//        c.Expect(user.Name, Equals, "John")
+	//        c.Expect(user.Age, Equals, 42)
+	//    })
+	AssertAll(closure func())
+
 	// Makes an assumption. Otherwise the same as an expectation,
 	// but on failure will not continue executing the child specs.
 	Assume(actual interface{}, matcher Matcher, expected ...interface{})
+
+	// Marks the current spec as skipped and stops executing it. Must be
+	// called from the goroutine which is executing the spec. If the spec
+	// had already failed, it will be reported as failed.
+	Skip(reason string)
+
+	// Same as Skip, but skips the spec only if the condition is true.
+	SkipIf(condition bool, reason string)
+
+	// Returns a Context whose expectations and assumptions report the
+	// location of their caller's caller, n levels up. Useful in helper
+	// functions, so that a failure points to the spec instead of the helper.
+	// For example:
+	//    func expectPositive(c Context, n int) {
+	//        c.SkipCallerFrames(1).Expect(n, IsPositive)
+	//    }
+	SkipCallerFrames(n int) Context
 }
 
 type taskContext struct {
@@ -34,6 +117,24 @@ type taskContext struct {
 	currentSpec    *specRun
 	executedSpecs  *list.List
 	postponedSpecs *list.List
+	filter         *specFilter
+	timeout        time.Duration
+	buffer         *errorBuffer
+	hooks          *onceHooks
+	fullStacks     bool
+	timedOutPaths  []path
+	mutex          sync.Mutex
+	detached       *taskSnapshot
+	aborted        chan struct{}
+}
+
+// Results of a task whose spec timed out. The spec keeps on running in the
+// background, so the results are copied when the timeout happens, and the
+// specs which the task would execute after it are ignored.
+type taskSnapshot struct {
+	executedSpecs  []*specRun
+	postponedSpecs []*specRun
+	timedOut       *specRun
 }
 
 func newInitialContext() *taskContext {
@@ -46,6 +147,7 @@ func newExplicitContext(targetPath path) *taskContext {
 	c.currentSpec = nil
 	c.executedSpecs = list.New()
 	c.postponedSpecs = list.New()
+	c.aborted = make(chan struct{})
 	return c
 }
 
@@ -55,14 +157,71 @@ func (c *taskContext) Specify(name string, closure func()) {
 	c.exitSpec()
 }
 
+func (c *taskContext) FSpecify(name string, closure func()) {
+	c.enterSpec(name, closure)
+	c.currentSpec.focused = true
+	c.processCurrentSpec()
+	c.exitSpec()
+}
+
+func (c *taskContext) PSpecify(name string, closure func()) {
+	c.enterSpec(name, closure)
+	c.currentSpec.pending = true
+	c.processCurrentSpec()
+	c.exitSpec()
+}
+
+func (c *taskContext) XSpecify(name string, closure func()) {
+	c.PSpecify(name, closure)
+}
+
+func (c *taskContext) BeforeEach(closure func()) {
+	c.currentSpec.beforeEach = append(c.currentSpec.beforeEach, closure)
+}
+
+func (c *taskContext) AfterEach(closure func()) {
+	c.currentSpec.afterEach = append(c.currentSpec.afterEach, closure)
+}
+
+func (c *taskContext) BeforeAll(closure func()) {
+	if e := c.hooks.runBeforeAll(c.nextHookKey(), closure); e != nil {
+		c.currentSpec.AddFatalError(e.ToError())
+	}
+}
+
+func (c *taskContext) AfterAll(closure func()) {
+	c.hooks.registerAfterAll(c.nextHookKey(), c.currentSpec, closure)
+}
+
+func (c *taskContext) DeferCleanup(closure func()) {
+	c.currentSpec.deferCleanup(closure)
+}
+
+func (c *taskContext) nextHookKey() string {
+	spec := c.currentSpec
+	spec.hookCount++
+	return fmt.Sprintf("%v %v %v", spec.rootParent().name, spec.path, spec.hookCount)
+}
+
 func (c *taskContext) enterSpec(name string, closure func()) {
 	spec := newSpecRun(name, closure, c.currentSpec, c.targetPath)
+	spec.pending = closure == nil
+	spec.timeout = c.timeout
 	c.currentSpec = spec
 }
 
 func (c *taskContext) processCurrentSpec() {
 	spec := c.currentSpec
+	buffer := c.buffer
+	c.buffer = nil
+	defer func() { c.buffer = buffer }()
 	switch {
+	case c.hasTimedOut(spec):
+		// reported by the task in which it timed out
+	case c.shouldExecute(spec) && c.isFilteredOut(spec):
+		c.skip(spec, "filtered out")
+	case c.shouldExecute(spec) && spec.pending:
+		c.record(c.executedSpecs, spec)
 	case c.shouldExecute(spec):
 		c.execute(spec)
 	case c.shouldPostpone(spec):
@@ -75,7 +234,7 @@ func (c *taskContext) exitSpec() {
 }
 
 func (c *taskContext) shouldExecute(spec *specRun) bool {
-	if spec.parent != nil && spec.parent.hasFatalErrors {
+	if spec.parent != nil && spec.parent.hasFailedFatally() {
 		return false
 	}
 	return spec.isOnTargetPath() || (spec.isUnseen() && spec.isFirstChild())
@@ -86,28 +245,216 @@ func (c *taskContext) shouldPostpone(spec *specRun) bool {
 }
 
 func (c *taskContext) execute(spec *specRun) {
-	c.executedSpecs.PushBack(spec)
-	spec.execute()
+	if c.record(c.executedSpecs, spec) {
+		spec.execute(c.detach)
+	}
+}
+
+// The root specs are always executed, because the spec functions are
+// selected with the -test.run parameter. Nested specs are executed when the
+// filter matches their full name or the full name of their parent, or when
+// the filter might match their children. The specs which were executed only
+// to find their children are skipped when the results are collected.
+func (c *taskContext) isFilteredOut(spec *specRun) bool {
+	if c.filter == nil {
+		return false
+	}
+	spec.matchesFilter = c.filter.matches(spec)
+	return spec.parent != nil && !spec.matchesFilter && !c.filter.mayMatchChildrenOf(spec)
+}
+
+func (c *taskContext) skip(spec *specRun, reason string) {
+	spec.skip(reason)
+	c.record(c.executedSpecs, spec)
 }
 
 func (c *taskContext) postpone(spec *specRun) {
-	c.postponedSpecs.PushBack(spec)
+	c.record(c.postponedSpecs, spec)
+}
+
+// Returns false if the context has been detached, in which case
+// the spec should not be executed.
+func (c *taskContext) record(specs *list.List, spec *specRun) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.detached != nil {
+		return false
+	}
+	specs.PushBack(spec)
+	return true
+}
+
+// Called when a spec times out. Takes a snapshot of the results, after
+// which the rest of the task is executed without recording its results,
+// until the spec which timed out returns. The children of the spec which
+// timed out will not be executed.
+func (c *taskContext) detach(spec *specRun, timeoutError *Error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.detached != nil {
+		return
+	}
+	snapshot := &taskSnapshot{timedOut: spec}
+	for _, executed := range asSpecArray(c.executedSpecs) {
+		result := executed.snapshot()
+		if executed == spec {
+			result.AddFatalError(timeoutError)
+		}
+		snapshot.executedSpecs = append(snapshot.executedSpecs, result)
+	}
+	for _, postponed := range asSpecArray(c.postponedSpecs) {
+		if !postponed.path.isBeyond(spec.path) {
+			snapshot.postponedSpecs = append(snapshot.postponedSpecs, postponed.snapshot())
+		}
+	}
+	c.detached = snapshot
+	close(c.aborted)
+}
+
+func (c *taskContext) snapshot() *taskSnapshot {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.detached
+}
+
+// Returns a context which continues executing the task after the spec
+// which timed out, so that the specs after it will be found.
+func (c *taskContext) resumeAfter(spec *specRun) *taskContext {
+	resumed := newExplicitContext(c.targetPath)
+	resumed.timeout = c.timeout
+	resumed.timedOutPaths = append(append([]path{}, c.timedOutPaths...), spec.path)
+	return resumed
+}
+
+func (c *taskContext) hasTimedOut(spec *specRun) bool {
+	for _, path := range c.timedOutPaths {
+		if spec.path.isEqual(path) {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *taskContext) Expect(actual interface{}, matcher Matcher, expected ...interface{}) {
-	location := callerLocation()
-	logger := expectationLogger{c.currentSpec}
-	m := newMatcherAdapter(location, logger, ExpectFailed)
-	m.Expect(actual, matcher, expected...)
+	c.expect(0, actual, matcher, expected...)
+}
+
+func (c *taskContext) ExpectThat(actual interface{}) *Expectation {
+	return &Expectation{c, 0, actual}
+}
+
+func (c *taskContext) SoftExpect(actual interface{}, matcher Matcher, expected ...interface{}) {
+	c.expect(0, actual, matcher, expected...)
 }
 
 func (c *taskContext) Assume(actual interface{}, matcher Matcher, expected ...interface{}) {
-	location := callerLocation()
-	logger := assumptionLogger{c.currentSpec}
-	m := newMatcherAdapter(location, logger, AssumeFailed)
+	c.assume(0, actual, matcher, expected...)
+}
+
+// Must be called directly from the method which the spec called, so that
+// the stack trace of a failure begins from the spec, after skipping
+// the given number of frames of helper functions.
+func (c *taskContext) expect(skip int, actual interface{}, matcher Matcher, expected ...interface{}) {
+	logger := expectationLogger{c.errorLog()}
+	m := newCallerMatcherAdapter(skip, c.fullStacks, logger, ExpectFailed)
 	m.Expect(actual, matcher, expected...)
 }
 
+func (c *taskContext) assume(skip int, actual interface{}, matcher Matcher, expected ...interface{}) {
+	logger := assumptionLogger{c.errorLog()}
+	m := newCallerMatcherAdapter(skip, c.fullStacks, logger, AssumeFailed)
+	m.Expect(actual, matcher, expected...)
+}
+
+func (c *taskContext) SkipCallerFrames(n int) Context {
+	return &skippingContext{c, n}
+}
+
+// Removes the frames of helper functions from the start of the stack traces.
+type skippingContext struct {
+	*taskContext
+	skip int
+}
+
+func (c *skippingContext) Expect(actual interface{}, matcher Matcher, expected ...interface{}) {
+	c.expect(c.skip, actual, matcher, expected...)
+}
+
+func (c *skippingContext) ExpectThat(actual interface{}) *Expectation {
+	return &Expectation{c.taskContext, c.skip, actual}
+}
+
+func (c *skippingContext) SoftExpect(actual interface{}, matcher Matcher, expected ...interface{}) {
+	c.expect(c.skip, actual, matcher, expected...)
+}
+
+func (c *skippingContext) Assume(actual interface{}, matcher Matcher, expected ...interface{}) {
+	c.assume(c.skip, actual, matcher, expected...)
+}
+
+func (c *skippingContext) SkipCallerFrames(n int) Context {
+	return &skippingContext{c.taskContext, c.skip + n}
+}
+
+func skipFrames(stacktrace []*Location, skip int) []*Location {
+	if skip >= len(stacktrace) {
+		return stacktrace[len(stacktrace):]
+	}
+	return stacktrace[skip:]
+}
+
+// Expectation is the subject of a fluent expectation,
+// which is started with Context.ExpectThat.
+type Expectation struct {
+	context *taskContext
+	skip    int
+	actual  interface{}
+}
+
+// Expects the actual value to match the matcher.
+func (this *Expectation) To(matcher Matcher, expected ...interface{}) {
+	this.context.expect(this.skip, this.actual, matcher, expected...)
+}
+
+// Expects the actual value to not match the matcher. Same as To(Not(matcher)).
+func (this *Expectation) NotTo(matcher Matcher, expected ...interface{}) {
+	this.context.expect(this.skip, this.actual, Not(matcher), expected...)
+}
+
+func (c *taskContext) AssertAll(closure func()) {
+	outer := c.buffer
+	c.buffer = newErrorBuffer()
+	exception := recoverOnPanic(closure)
+	buffer := c.buffer
+	c.buffer = outer
+
+	buffer.flushTo(c.errorLog())
+	if _, ok := exceptionCause(exception).(specSkipped); ok {
+		panic(exception.Cause)
+	}
+	if exception != nil {
+		c.errorLog().AddError(exception.ToError())
+	}
+}
+
+// Errors are buffered inside AssertAll, and otherwise logged to the spec.
+func (c *taskContext) errorLog() ratedErrorLogger {
+	if c.buffer != nil {
+		return c.buffer
+	}
+	return c.currentSpec
+}
+
+func (c *taskContext) Skip(reason string) {
+	panic(specSkipped{reason})
+}
+
+func (c *taskContext) SkipIf(condition bool, reason string) {
+	if condition {
+		c.Skip(reason)
+	}
+}
+
 type expectationLogger struct {
 	log ratedErrorLogger
 }
@@ -5,8 +5,11 @@
 package gospec
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"sort"
+	"strings"
 )
 
 func ContextSpec(c nanospec.Context) {
@@ -63,4 +66,439 @@ func ContextSpec(c nanospec.Context) {
 		c.Expect(runCounts[fmt.Sprintf("%v.DummySpecWithOneChild", pkgPath)]).Equals(1)
 		c.Expect(runCounts[fmt.Sprintf("%v.DummySpecWithTwoChildren", pkgPath)]).Equals(2)
 	})
+
+	c.Specify("When filtering specs by name", func() {
+		executed := []string{}
+		spec := func(c Context) {
+			executed = append(executed, "root")
+			c.Specify("Child A", func() {
+				executed = append(executed, "a")
+				c.Specify("Child AA", func() {
+					executed = append(executed, "aa")
+				})
+			})
+			c.Specify("Child B", func() {
+				executed = append(executed, "b")
+				c.Specify("Child BA", func() {
+					executed = append(executed, "ba")
+				})
+			})
+		}
+		runWithFilter := func(pattern string) *ResultCollector {
+			r := NewRunner()
+			r.AddNamedSpec("RootSpec", spec)
+			c.Expect(r.FilterByName(pattern)).Equals(nil)
+			r.RunParallel(1)
+			return r.Results()
+		}
+
+		c.Specify("the specs which do not match are executed to find their children", func() {
+			runWithFilter("Child B")
+			c.Expect(strings.Join(executed, ",")).Equals("root,a,aa,root,b,ba")
+		})
+		c.Specify("the specs whose children cannot match an anchored pattern are not executed", func() {
+			runWithFilter("^RootSpec Child B")
+			c.Expect(strings.Join(executed, ",")).Equals("root,root,b,ba")
+		})
+		c.Specify("the parents of a matching nested spec are executed", func() {
+			results := runWithFilter("Child BA")
+			c.Expect(results.PassCount()).Equals(3)
+			c.Expect(results.SkipCount()).Equals(2)
+
+			recorder := new(recordingReporter)
+			results.Report(recorder)
+			c.Expect(recorder.String()).Equals("" +
+				"started 0 RootSpec\n" +
+				"passed 0 RootSpec\n" +
+				"started 1 Child A\n" +
+				"skipped 1 Child A filtered out\n" +
+				"started 2 Child AA\n" +
+				"skipped 2 Child AA filtered out\n" +
+				"started 1 Child B\n" +
+				"passed 1 Child B\n" +
+				"started 2 Child BA\n" +
+				"passed 2 Child BA\n" +
+				"finished 3 0 2\n")
+		})
+		c.Specify("the children of matching specs are executed", func() {
+			results := runWithFilter("^RootSpec Child A$")
+			c.Expect(results.PassCount()).Equals(3)
+			c.Expect(strings.Join(executed, ",")).Equals("root,a,aa,root")
+		})
+		c.Specify("the specs which do not match are counted as skipped", func() {
+			results := runWithFilter("^RootSpec Child B")
+			c.Expect(results.PassCount()).Equals(3)
+			c.Expect(results.SkipCount()).Equals(1)
+			c.Expect(results.TotalCount()).Equals(4)
+
+			recorder := new(recordingReporter)
+			results.Report(recorder)
+			c.Expect(recorder.String()).Equals("" +
+				"started 0 RootSpec\n" +
+				"passed 0 RootSpec\n" +
+				"started 1 Child A\n" +
+				"skipped 1 Child A filtered out\n" +
+				"started 1 Child B\n" +
+				"passed 1 Child B\n" +
+				"started 2 Child BA\n" +
+				"passed 2 Child BA\n" +
+				"finished 3 0 1\n")
+		})
+		c.Specify("the failures of the specs which do not match are not reported", func() {
+			r := NewRunner()
+			r.AddNamedSpec("Root", func(c Context) {
+				c.Specify("Parent", func() {
+					c.Specify("leaf", func() {})
+					c.Specify("other", func() {
+						c.Expect(1, Equals, 2)
+					})
+				})
+			})
+			c.Expect(r.FilterByName("leaf")).Equals(nil)
+			r.Run()
+			results := r.Results()
+
+			recorder := new(recordingReporter)
+			results.Report(recorder)
+			c.Expect(recorder.String()).Equals("" +
+				"started 0 Root\n" +
+				"passed 0 Root\n" +
+				"started 1 Parent\n" +
+				"passed 1 Parent\n" +
+				"started 2 leaf\n" +
+				"passed 2 leaf\n" +
+				"started 2 other\n" +
+				"skipped 2 other filtered out\n" +
+				"finished 3 0 1\n")
+		})
+		c.Specify("an invalid pattern is an error", func() {
+			r := NewRunner()
+			c.Expect(r.FilterByName("(") != nil).IsTrue()
+		})
+	})
+
+	c.Specify("When some of the specs are focused", func() {
+		executed := []string{}
+		spec := func(c Context) {
+			c.Specify("Child A", func() {
+				executed = append(executed, "a")
+				c.Specify("Child AA", func() {
+					executed = append(executed, "aa")
+				})
+				c.Specify("Child AB", func() {
+					executed = append(executed, "ab")
+				})
+			})
+			c.FSpecify("Child B", func() {
+				executed = append(executed, "b")
+				c.Specify("Child BA", func() {
+					executed = append(executed, "ba")
+				})
+			})
+			c.Specify("Child C", func() {
+				executed = append(executed, "c")
+				c.Expect(1, Equals, 2)
+			})
+		}
+		r := NewRunner()
+		r.AddNamedSpec("RootSpec", spec)
+		r.RunParallel(1)
+		results := r.Results()
+
+		c.Specify("the specs which are not in focus are executed to find the focused specs", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("a,aa,c,b,ba,a,ab")
+		})
+		c.Specify("the specs which are not in focus are counted as skipped", func() {
+			c.Expect(results.PassCount()).Equals(3)
+			c.Expect(results.FailCount()).Equals(0)
+			c.Expect(results.SkipCount()).Equals(4)
+
+			recorder := new(argumentsReporter)
+			results.Report(recorder)
+			c.Expect(recorder.stats.Focused).IsTrue()
+		})
+	})
+
+	c.Specify("When a focused spec is inside a spec which is not in focus", func() {
+		executed := []string{}
+		r := NewRunner()
+		r.AddNamedSpec("Root", func(c Context) {
+			c.Specify("A", func() {
+				c.FSpecify("A1", func() {
+					executed = append(executed, "a1")
+				})
+			})
+			c.Specify("B", func() {
+				c.Specify("B0", func() {
+					executed = append(executed, "b0")
+				})
+				c.FSpecify("B1", func() {
+					executed = append(executed, "b1")
+				})
+			})
+		})
+		r.RunParallel(1)
+		results := r.Results()
+
+		c.Specify("the focused spec is executed", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("a1,b0,b1")
+		})
+		c.Specify("the focused spec and its parents are reported", func() {
+			recorder := new(recordingReporter)
+			results.Report(recorder)
+			c.Expect(recorder.String()).Equals("" +
+				"started 0 Root\n" +
+				"passed 0 Root\n" +
+				"started 1 A\n" +
+				"passed 1 A\n" +
+				"started 2 A1\n" +
+				"passed 2 A1\n" +
+				"started 1 B\n" +
+				"passed 1 B\n" +
+				"started 2 B0\n" +
+				"skipped 2 B0 not focused\n" +
+				"started 2 B1\n" +
+				"passed 2 B1\n" +
+				"finished 5 0 1\n")
+		})
+	})
+
+	c.Specify("When none of the specs are focused, all specs are executed", func() {
+		r := NewRunner()
+		r.AddSpec(DummySpecWithTwoChildren)
+		r.Run()
+		results := r.Results()
+
+		c.Expect(results.PassCount()).Equals(3)
+		c.Expect(results.SkipCount()).Equals(0)
+		recorder := new(argumentsReporter)
+		results.Report(recorder)
+		c.Expect(recorder.stats.Focused).IsFalse()
+	})
+
+	c.Specify("When some of the specs are pending", func() {
+		executed := []string{}
+		results := runSpec(func(c Context) {
+			c.Specify("Child A", func() {
+				executed = append(executed, "a")
+			})
+			c.PSpecify("Child B", func() {
+				executed = append(executed, "b")
+			})
+			c.XSpecify("Child C", func() {
+				executed = append(executed, "c")
+			})
+			c.Specify("Child D", nil)
+		})
+
+		c.Specify("the bodies of the pending specs are never executed", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("a")
+		})
+		c.Specify("the pending specs are counted separately", func() {
+			c.Expect(results.PassCount()).Equals(2)
+			c.Expect(results.PendingCount()).Equals(3)
+			c.Expect(results.TotalCount()).Equals(5)
+
+			recorder := new(recordingReporter)
+			results.Report(recorder)
+			c.Expect(recorder.String()).Equals("" +
+				"started 0 RootSpec\n" +
+				"passed 0 RootSpec\n" +
+				"started 1 Child A\n" +
+				"passed 1 Child A\n" +
+				"started 1 Child B\n" +
+				"pending 1 Child B\n" +
+				"started 1 Child C\n" +
+				"pending 1 Child C\n" +
+				"started 1 Child D\n" +
+				"pending 1 Child D\n" +
+				"finished 2 0 0\n")
+		})
+	})
+
+	c.Specify("When a spec is skipped", func() {
+		executed := []string{}
+		results := runSpec(func(c Context) {
+			c.Specify("Skipped", func() {
+				executed = append(executed, "before")
+				c.Skip("not supported")
+				executed = append(executed, "after")
+			})
+			c.Specify("Skipped if true", func() {
+				c.SkipIf(true, "condition is true")
+				executed = append(executed, "if true")
+			})
+			c.Specify("Not skipped if false", func() {
+				c.SkipIf(false, "condition is false")
+				executed = append(executed, "if false")
+			})
+			c.Specify("Failed before skipping", func() {
+				c.Expect(1, Equals, 2)
+				c.Skip("too late")
+			})
+		})
+
+		c.Specify("the code after skipping is not executed", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("before,if false")
+		})
+		c.Specify("the skipped specs are counted as skipped and not failed", func() {
+			c.Expect(results.PassCount()).Equals(2)
+			c.Expect(results.SkipCount()).Equals(2)
+			c.Expect(results.FailCount()).Equals(1)
+
+			recorder := new(recordingReporter)
+			results.Report(recorder)
+			c.Expect(recorder.String()).Equals("" +
+				"started 0 RootSpec\n" +
+				"passed 0 RootSpec\n" +
+				"started 1 Skipped\n" +
+				"skipped 1 Skipped not supported\n" +
+				"started 1 Skipped if true\n" +
+				"skipped 1 Skipped if true condition is true\n" +
+				"started 1 Not skipped if false\n" +
+				"passed 1 Not skipped if false\n" +
+				"started 1 Failed before skipping\n" +
+				"failed 1 Failed before skipping 1\n" +
+				"finished 2 1 2\n")
+		})
+	})
+
+	c.Specify("When a failing spec is retried", func() {
+		debug := new(bytes.Buffer)
+		calls := 0
+		failsOnFirstCall := func(c Context) {
+			c.Specify("Child A", func() {
+				calls++
+				c.Expect(calls, Equals, 2)
+			})
+			c.Specify("Child B", func() {})
+		}
+		runWithRetries := func(retries int) *ResultCollector {
+			r := NewRunner()
+			r.SetDebugOutput(debug)
+			r.AddNamedSpec("RootSpec", failsOnFirstCall, WithRetries(retries))
+			r.RunParallel(1)
+			return r.Results()
+		}
+
+		c.Specify("the spec passes if a retry passes", func() {
+			results := runWithRetries(1)
+			c.Expect(calls).Equals(2)
+			c.Expect(results.PassCount()).Equals(3)
+			c.Expect(results.FailCount()).Equals(0)
+		})
+		c.Specify("the spec fails if all attempts fail", func() {
+			calls = -10
+			results := runWithRetries(2)
+			c.Expect(calls).Equals(-7)
+			c.Expect(results.FailCount()).Equals(1)
+
+			reporter := new(argumentsReporter)
+			results.Report(reporter)
+			c.Expect(len(reporter.errors)).Equals(2)
+			c.Expect(reporter.errors[0].Actual).Equals("-7")
+			c.Expect(reporter.errors[1].Message).Equals("Failed after 3 attempts")
+		})
+		c.Specify("the retries are written to the debug output", func() {
+			runWithRetries(1)
+			c.Expect(debug.String()).Equals("DEBUG: Retrying spec 'RootSpec Child A' after a failure (attempt 2 of 2)\n")
+		})
+		c.Specify("without retries, the spec is executed only once", func() {
+			results := runWithRetries(0)
+			c.Expect(calls).Equals(1)
+			c.Expect(results.FailCount()).Equals(1)
+			c.Expect(debug.Len()).Equals(0)
+		})
+	})
+
+	c.Specify("When specs are executed in random order", func() {
+		executionOrder := func(seed int64) string {
+			executed := []string{}
+			spec := func(c Context) {
+				for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+					name := name
+					c.Specify(name, func() {
+						executed = append(executed, name)
+					})
+				}
+			}
+			r := NewRunner()
+			r.AddNamedSpec("Root 1", spec)
+			r.AddNamedSpec("Root 2", spec)
+			r.AddNamedSpec("Root 3", spec)
+			r.RandomizeOrder(seed)
+			r.RunParallel(1)
+			return strings.Join(executed, "")
+		}
+
+		c.Specify("the same seed gives the same order", func() {
+			c.Expect(executionOrder(42)).Equals(executionOrder(42))
+		})
+		c.Specify("different seeds give different orders", func() {
+			c.Expect(executionOrder(1) != executionOrder(2)).IsTrue()
+		})
+		c.Specify("all the specs are executed", func() {
+			order := []byte(executionOrder(42))
+			sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+			c.Expect(string(order)).Equals("aaabbbcccdddeeefff")
+		})
+		c.Specify("a random seed is chosen when the seed is zero", func() {
+			r := NewRunner()
+			c.Expect(r.RandomizeOrder(0) != 0).IsTrue()
+			c.Expect(r.RandomizeOrder(123)).Equals(int64(123))
+		})
+	})
+
+	c.Specify("When failing fast", func() {
+		executed := []string{}
+		spec := func(c Context) {
+			// The postponed specs are executed in the reverse order,
+			// so these will be executed in the order of their names.
+			for _, name := range []string{"1", "6", "5", "4", "3", "2"} {
+				name := name
+				c.Specify("Spec "+name, func() {
+					executed = append(executed, name)
+					c.Expect(name != "2" && name != "5", IsTrue)
+				})
+			}
+		}
+		r := NewRunner()
+		r.AddNamedSpec("RootSpec", spec)
+		r.FailFast()
+		r.RunParallel(1)
+		results := r.Results()
+
+		c.Specify("the specs are not executed after the first failure", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("1,2")
+		})
+		c.Specify("the specs which were not executed are not counted", func() {
+			c.Expect(results.PassCount()).Equals(2)
+			c.Expect(results.FailCount()).Equals(1)
+			c.Expect(results.TotalCount()).Equals(3)
+
+			reporter := new(argumentsReporter)
+			results.Report(reporter)
+			c.Expect(reporter.stats.Stopped).IsTrue()
+		})
+	})
+
+	c.Specify("When failing fast without limiting the number of workers", func() {
+		executed := []string{}
+		r := NewRunner()
+		// The root specs are executed in the reverse order of adding them.
+		for _, name := range []string{"5", "4", "3", "2", "1"} {
+			name := name
+			r.AddNamedSpec("Spec "+name, func(c Context) {
+				executed = append(executed, name)
+				c.Expect(name != "2" && name != "5", IsTrue)
+			})
+		}
+		r.FailFast()
+		r.Run()
+
+		c.Specify("the root specs are not executed after the first failure", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("1,2")
+			c.Expect(r.Results().TotalCount()).Equals(2)
+		})
+	})
 }
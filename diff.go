@@ -0,0 +1,179 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Splits the Go syntax representation of a value into lines, so that every
+// struct field, slice element and map entry is on its own line.
+func goSyntaxLines(value interface{}) []string {
+	s := fmt.Sprintf("%#v", value)
+	lines := []string{}
+	line := ""
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0:
+			line += string(ch)
+			if ch == '\\' && i+1 < len(s) {
+				i++
+				line += string(s[i])
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+			line += string(ch)
+		case ch == '{' && i+1 < len(s) && s[i+1] == '}':
+			line += "{}"
+			i++
+		case ch == '{':
+			lines = append(lines, line+"{")
+			depth++
+			line = indent(depth)
+		case ch == '}':
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line+",")
+			}
+			depth--
+			line = indent(depth) + "}"
+		case ch == ',':
+			lines = append(lines, line+",")
+			line = indent(depth)
+			if i+1 < len(s) && s[i+1] == ' ' {
+				i++
+			}
+		default:
+			line += string(ch)
+		}
+	}
+	return append(lines, line)
+}
+
+// Compares the lines using the Myers diff algorithm, and marks the lines
+// which exist only in the expected lines with "-" and the lines which exist
+// only in the actual lines with "+".
+func lineDiff(expected []string, actual []string) string {
+	diff := []string{"--- expected", "+++ actual"}
+	for _, edit := range shortestEditScript(expected, actual) {
+		diff = append(diff, edit.String())
+	}
+	return strings.Join(diff, "\n")
+}
+
+type diffEdit struct {
+	marker string
+	line   string
+}
+
+func (this diffEdit) String() string {
+	return this.marker + " " + this.line
+}
+
+// Eugene W. Myers: "An O(ND) Difference Algorithm and Its Variations".
+// Finds the furthest reaching path on every diagonal k = x - y, for an
+// increasing number of edits d, until the end of both lines is reached.
+// The path is then followed backwards using the saved states of every round.
+func shortestEditScript(a []string, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	trace := [][]int{}
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int{}, v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				return backtrackEdits(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrackEdits(a []string, b []string, trace [][]int) []diffEdit {
+	max := len(a) + len(b)
+	edits := []diffEdit{}
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, diffEdit{" ", a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, diffEdit{"+", b[prevY]})
+			} else {
+				edits = append(edits, diffEdit{"-", a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// Line diff between the Go syntax representations of the values, or an
+// empty string when both values fit on one line, in which case the values
+// themselves are easy enough to compare.
+func formatDiff(expected interface{}, actual interface{}) string {
+	expectedLines := goSyntaxLines(expected)
+	actualLines := goSyntaxLines(actual)
+	if len(expectedLines) <= 1 && len(actualLines) <= 1 {
+		return ""
+	}
+	return lineDiff(expectedLines, actualLines)
+}
+
+// Lazily computed diff between the Go syntax representations of two values.
+type valueDiff struct {
+	expected interface{}
+	actual   interface{}
+}
+
+func (this valueDiff) String() string {
+	return lineDiff(goSyntaxLines(this.expected), goSyntaxLines(this.actual))
+}
+
+// Lazily computed diff which is appended to a message on its own lines,
+// or nothing when formatDiff finds that the values are on one line.
+type appendedDiff struct {
+	expected interface{}
+	actual   interface{}
+}
+
+func (this appendedDiff) String() string {
+	if diff := formatDiff(this.expected, this.actual); diff != "" {
+		return "\n" + diff
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+)
+
+type diffDummy struct {
+	Name string
+	Tags []string
+	Meta diffDummyMeta
+}
+
+type diffDummyMeta struct {
+	Age int
+}
+
+func DiffSpec(c nanospec.Context) {
+
+	c.Specify("Values are split into one line per field and element", func() {
+		lines := goSyntaxLines(diffDummy{"Alice", []string{"a", "b"}, diffDummyMeta{30}})
+		c.Expect(strings.Join(lines, "\n")).Equals(`gospec.diffDummy{
+  Name:"Alice",
+  Tags:[]string{
+    "a",
+    "b",
+  },
+  Meta:gospec.diffDummyMeta{
+    Age:30,
+  },
+}`)
+	})
+	c.Specify("Separators inside strings are not split", func() {
+		lines := goSyntaxLines([]string{"a, {b}", `"c}`})
+		c.Expect(strings.Join(lines, "\n")).Equals(`[]string{
+  "a, {b}",
+  "\"c}",
+}`)
+	})
+	c.Specify("Empty collections stay on one line", func() {
+		lines := goSyntaxLines(diffDummy{})
+		c.Expect(lines[2]).Equals("  Tags:[]string(nil),")
+		c.Expect(strings.Join(goSyntaxLines([]int{}), "\n")).Equals("[]int{}")
+	})
+
+	c.Specify("Lines which differ are marked in the diff", func() {
+		diff := lineDiff(
+			[]string{"a", "b", "c", "d"},
+			[]string{"a", "c", "x", "d"})
+		c.Expect(diff).Equals(`--- expected
++++ actual
+  a
+- b
+  c
++ x
+  d`)
+	})
+	c.Specify("Identical lines have no markers in the diff", func() {
+		diff := lineDiff([]string{"a", "b"}, []string{"a", "b"})
+		c.Expect(diff).Equals("--- expected\n+++ actual\n  a\n  b")
+	})
+	c.Specify("Lines which exist on only one side are all marked", func() {
+		c.Expect(lineDiff([]string{}, []string{"a"})).Equals("--- expected\n+++ actual\n+ a")
+		c.Expect(lineDiff([]string{"a"}, []string{})).Equals("--- expected\n+++ actual\n- a")
+	})
+	c.Specify("Lines which are all different are all marked", func() {
+		diff := lineDiff([]string{"a", "b", "c"}, []string{"x", "y"})
+		c.Expect(diff).Equals("--- expected\n+++ actual\n- a\n- b\n- c\n+ x\n+ y")
+	})
+	c.Specify("The shortest diff is found when lines are both removed and added", func() {
+		diff := lineDiff(
+			[]string{"a", "b", "c", "a", "b", "b", "a"},
+			[]string{"c", "b", "a", "b", "a", "c"})
+		removed := strings.Count(diff, "\n- ")
+		added := strings.Count(diff, "\n+ ")
+		c.Expect(removed + added).Equals(5)
+	})
+
+	c.Specify("Values which are on one line have no diff", func() {
+		c.Expect(formatDiff(1, 2)).Equals("")
+		c.Expect(formatDiff("a, b", "a, c")).Equals("")
+	})
+	c.Specify("Values which are on many lines have a diff", func() {
+		diff := formatDiff([]int{1, 2}, []int{1, 3})
+		c.Expect(diff).Equals(`--- expected
++++ actual
+  []int{
+    1,
+-   2,
++   3,
+  }`)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DotReporter prints one character for every spec: "." for passing, "F" for
+// failing, "S" for skipped and "P" for pending specs. When the suite has
+// finished, it prints a summary and the details of the failures. Suitable for
+// large suites, where listing every spec would be too verbose. The output is
+// flushed after every spec, so when the reporter is given to Runner.ReportTo,
+// the dots show the progress of the execution.
+type DotReporter struct {
+	out      io.Writer
+	path     []string
+	failures []dotFailure
+}
+
+type dotFailure struct {
+	name   string
+	errors []*Error
+}
+
+func NewDotReporter(out io.Writer) *DotReporter {
+	return &DotReporter{out, []string{}, []dotFailure{}}
+}
+
+func (this *DotReporter) SpecStarted(nestingLevel int, name string) {
+	this.path = append(this.path[:nestingLevel], name)
+}
+
+func (this *DotReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.print(".")
+}
+
+func (this *DotReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	this.print("F")
+	this.failures = append(this.failures, dotFailure{strings.Join(this.path, " / "), errors})
+}
+
+func (this *DotReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	this.print("S")
+}
+
+func (this *DotReporter) SpecPending(nestingLevel int, name string) {
+	this.print("P")
+}
+
+func (this *DotReporter) SuiteFinished(stats Stats) {
+	summary := fmt.Sprintf("%v passed, %v failed, %v pending", stats.PassCount, stats.FailCount, stats.PendingCount)
+	if stats.SkipCount > 0 {
+		summary += fmt.Sprintf(", %v skipped", stats.SkipCount)
+	}
+	fmt.Fprintf(this.out, "\n\n%v in %.2fs\n", summary, stats.Duration.Seconds())
+	for i, failure := range this.failures {
+		fmt.Fprintf(this.out, "\n%v) %v\n", i+1, failure.name)
+		for _, error := range failure.errors {
+			fmt.Fprint(this.out, formatErrorMessage(error))
+			for _, loc := range error.StackTrace {
+				fmt.Fprintf(this.out, "    at %v\n", loc)
+			}
+		}
+	}
+}
+
+func (this *DotReporter) print(s string) {
+	fmt.Fprint(this.out, s)
+	flushIfBuffered(this.out)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"io"
+	"strings"
+	"time"
+)
+
+func DotSpec(c nanospec.Context) {
+	trim := strings.TrimSpace
+	out := new(bytes.Buffer)
+	reporter := NewDotReporter(out)
+
+	c.Specify("Prints a dot for passing and F for failing specs", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reportSpec(reporter, 1, "Passing", noErrors, 0)
+		reportSpec(reporter, 1, "Failing", someError, 0)
+		reportSpec(reporter, 2, "Passing", noErrors, 0)
+		c.Expect(out.String()).Equals("..F.")
+	})
+
+	c.Specify("Prints a summary and the failures at the end", func() {
+		location := &Location{"gospec.SomeSpec", "/path/to/some_test.go", 42}
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reportSpec(reporter, 1, "Failing", []*Error{newError(ExpectFailed, "equals “20”", "10", []*Location{location})}, 0)
+		reportSpec(reporter, 2, "Nested", someError, 0)
+		reporter.SuiteFinished(Stats{PassCount: 1, FailCount: 2, Duration: 1230 * time.Millisecond})
+		c.Expect(trim(out.String())).Equals(trim(`
+.FF
+
+1 passed, 2 failed, 0 pending in 1.23s
+
+1) RootSpec / Failing
+*** Expected: equals “20”
+         got: “10”
+    at some_test.go:42
+
+2) RootSpec / Failing / Nested
+*** some error
+`))
+	})
+
+	c.Specify("Prints S for skipped specs and counts them in the summary", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reporter.SpecStarted(1, "Skipped")
+		reporter.SpecSkipped(1, "Skipped", "not supported")
+		reporter.SuiteFinished(Stats{PassCount: 1, SkipCount: 1})
+		c.Expect(trim(out.String())).Equals(trim(`
+.S
+
+1 passed, 0 failed, 0 pending, 1 skipped in 0.00s
+`))
+	})
+
+	c.Specify("Prints P for pending specs and counts them in the summary", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reporter.SpecStarted(1, "Pending")
+		reporter.SpecPending(1, "Pending")
+		reporter.SuiteFinished(Stats{PassCount: 1, PendingCount: 1})
+		c.Expect(trim(out.String())).Equals(trim(`
+.P
+
+1 passed, 0 failed, 1 pending in 0.00s
+`))
+	})
+
+	c.Specify("Flushes buffered output after every spec", func() {
+		buffered := bufio.NewWriter(out)
+		reporter := NewDotReporter(buffered)
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reportSpec(reporter, 1, "Failing", someError, 0)
+		c.Expect(out.String()).Equals(".F")
+	})
+
+	c.Specify("Prints the dots of the finished specs before Run returns", func() {
+		whileRunning, afterRun := reportWhileRunning(func(out io.Writer) Reporter {
+			return NewDotReporter(out)
+		}, func(output string) bool {
+			return len(output) == 2
+		})
+		c.Expect(whileRunning).Equals("..")
+		c.Expect(strings.HasPrefix(afterRun, "...\n\n3 passed, 0 failed, 0 pending in ")).IsTrue()
+	})
+}
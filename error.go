@@ -4,7 +4,9 @@
 
 package gospec
 
-import ()
+import (
+	"sync"
+)
 
 type ErrorType int
 
@@ -19,10 +21,35 @@ type Error struct {
 	Message    string
 	Actual     string
 	StackTrace []*Location
+
+	// Name of the matcher which failed, for example "Equals", and the
+	// actual value before it was formatted into Actual. Not set for errors
+	// which do not come from a matcher, such as panics.
+	MatcherName string
+	ActualValue interface{}
+}
+
+// Name of the error type for machine-readable reports.
+func errorTypeName(t ErrorType) string {
+	switch t {
+	case ExpectFailed:
+		return "expectation"
+	case AssumeFailed:
+		return "assumption"
+	}
+	return "error"
 }
 
 func newError(errortype ErrorType, message string, actual string, stacktrace []*Location) *Error {
-	return &Error{errortype, message, actual, stacktrace}
+	return &Error{Type: errortype, Message: message, Actual: actual, StackTrace: stacktrace}
+}
+
+// Location where the error happened, or nil if it is not known.
+func (this *Error) Location() *Location {
+	if len(this.StackTrace) > 0 {
+		return this.StackTrace[0]
+	}
+	return nil
 }
 
 func (this *Error) equals(that *Error) bool {
@@ -59,3 +86,43 @@ type ratedErrorLogger interface {
 	errorLogger
 	AddFatalError(error *Error)
 }
+
+// Collects errors so that they can be logged later all at once.
+type errorBuffer struct {
+	mutex  sync.Mutex
+	errors []*Error
+	fatal  []bool
+}
+
+func newErrorBuffer() *errorBuffer {
+	return &errorBuffer{}
+}
+
+func (this *errorBuffer) AddError(error *Error) {
+	this.add(error, false)
+}
+
+func (this *errorBuffer) AddFatalError(error *Error) {
+	this.add(error, true)
+}
+
+func (this *errorBuffer) add(error *Error, fatal bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.errors = append(this.errors, error)
+	this.fatal = append(this.fatal, fatal)
+}
+
+func (this *errorBuffer) flushTo(log ratedErrorLogger) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for i, error := range this.errors {
+		if this.fatal[i] {
+			log.AddFatalError(error)
+		} else {
+			log.AddError(error)
+		}
+	}
+	this.errors = nil
+	this.fatal = nil
+}
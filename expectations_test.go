@@ -5,7 +5,9 @@
 package gospec
 
 import (
+	"fmt"
 	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
 )
 
 func ExpectationsSpec(c nanospec.Context) {
@@ -53,6 +55,53 @@ func ExpectationsSpec(c nanospec.Context) {
 		})
 	})
 
+	c.Specify("When a spec has many failing soft expectations", func() {
+		results := runSpec(func(c Context) {
+			c.SoftExpect(1, Equals, 2)
+			c.SoftExpect(3, Equals, 4)
+			c.Specify("Child", func() {})
+		})
+
+		c.Specify("then all the failures are reported", func() {
+			c.Expect(messagesOfErrors(results)).Equals([]string{"equals “2”", "equals “4”"})
+		})
+		c.Specify("then its children are executed", func() {
+			c.Expect(results.TotalCount()).Equals(2)
+		})
+	})
+
+	c.Specify("When a spec asserts many things at once", func() {
+		results := runSpec(func(c Context) {
+			c.AssertAll(func() {
+				c.Expect(1, Equals, 2)
+				c.Expect(3, Equals, 4)
+				panic("boom")
+			})
+			c.Expect(5, Equals, 6)
+			c.Specify("Child", func() {
+				c.Expect(7, Equals, 8)
+			})
+		})
+
+		c.Specify("then all the failures are reported", func() {
+			c.Expect(messagesOfErrors(results)).Equals([]string{"equals “2”", "equals “4”", "panic: boom", "equals “6”"})
+		})
+		c.Specify("then the spec continues after a panic", func() {
+			c.Expect(results.TotalCount()).Equals(2)
+			c.Expect(results.FailCount()).Equals(2)
+		})
+	})
+
+	c.Specify("A spec can be skipped inside AssertAll", func() {
+		results := runSpec(func(c Context) {
+			c.AssertAll(func() {
+				c.Skip("not supported")
+			})
+			c.Expect(1, Equals, 2)
+		})
+		c.Expect(results.SkipCount()).Equals(1)
+	})
+
 	c.Specify("The location of a failed expectation is reported", func() {
 		results := runSpec(func(c Context) {
 			c.Expect(1, Equals, 2)
@@ -65,6 +114,177 @@ func ExpectationsSpec(c nanospec.Context) {
 		})
 		c.Expect(fileOfError(results)).Equals("expectations_test.go")
 	})
+
+	c.Specify("Fluent expectations behave the same as the other expectations", func() {
+		fluent := runSpec(func(c Context) {
+			c.ExpectThat(1).To(Equals, 1)
+			c.ExpectThat(1).To(Equals, 2)
+			c.ExpectThat(1).NotTo(Equals, 2)
+			c.ExpectThat(1).NotTo(Equals, 1)
+			c.ExpectThat("x").To(IsPositive)
+		})
+		plain := runSpec(func(c Context) {
+			c.Expect(1, Equals, 1)
+			c.Expect(1, Equals, 2)
+			c.Expect(1, Not(Equals), 2)
+			c.Expect(1, Not(Equals), 1)
+			c.Expect("x", IsPositive)
+		})
+		c.Expect(strings.Join(messagesOfErrors(fluent), "\n")).Equals(strings.Join(messagesOfErrors(plain), "\n"))
+		c.Expect(len(messagesOfErrors(fluent))).Equals(3)
+		c.Expect(fileOfError(fluent)).Equals("expectations_test.go")
+	})
+	c.Specify("Fluent expectations report the line where they were made", func() {
+		expectedLine := 0
+		results := runSpec(func(c Context) {
+			expectedLine = currentLocation().Line() + 1
+			c.ExpectThat(1).To(Equals, 2)
+		})
+		c.Expect(stackTraceOfError(results)[0].Line()).Equals(expectedLine)
+	})
+	c.Specify("Negated fluent expectations report the line where they were made", func() {
+		expectedLine := 0
+		results := runSpec(func(c Context) {
+			expectedLine = currentLocation().Line() + 1
+			c.ExpectThat(1).NotTo(Equals, 1)
+		})
+		c.Expect(stackTraceOfError(results)[0].FileName()).Equals("expectations_test.go")
+		c.Expect(stackTraceOfError(results)[0].Line()).Equals(expectedLine)
+	})
+	c.Specify("Fluent expectations can skip the frames of helper functions", func() {
+		expectedLine := 0
+		results := runSpec(func(c Context) {
+			expectedLine = currentLocation().Line() + 1
+			fluentExpectPositiveInHelper(c, -1)
+		})
+		c.Expect(stackTraceOfError(results)[0].Line()).Equals(expectedLine)
+	})
+
+	c.Specify("When a helper function skips its own frame", func() {
+		expectedLine := 0
+		results := runSpec(func(c Context) {
+			expectedLine = currentLocation().Line() + 1
+			expectPositiveInHelper(c, -1)
+		})
+		stacktrace := stackTraceOfError(results)
+
+		c.Specify("then the location is where the helper was called", func() {
+			c.Expect(stacktrace[0].FileName()).Equals("expectations_test.go")
+			c.Expect(stacktrace[0].Line()).Equals(expectedLine)
+		})
+		c.Specify("then the helper is not in the stack trace", func() {
+			for _, loc := range stacktrace {
+				c.Expect(loc.FuncName() != "expectPositiveInHelper").IsTrue()
+			}
+		})
+	})
+	c.Specify("Frames can be skipped also for assumptions and nested helpers", func() {
+		expectedLine := 0
+		results := runSpec(func(c Context) {
+			expectedLine = currentLocation().Line() + 1
+			assumePositiveInNestedHelper(c, -1)
+		})
+		c.Expect(stackTraceOfError(results)[0].Line()).Equals(expectedLine)
+	})
+
+	c.Specify("The matcher and the actual value of a failed expectation are reported", func() {
+		recorder := new(argumentsReporter)
+		runSpec(func(c Context) {
+			c.Expect([]int{1, 2}, HasLength, 3)
+		}).Report(recorder)
+		error := recorder.errors[0]
+		c.Expect(error.MatcherName).Equals("HasLength")
+		c.Expect(error.Actual).Equals("[1 2]")
+		c.Expect(len(error.ActualValue.([]int))).Equals(2)
+		c.Expect(error.Location().FileName()).Equals("expectations_test.go")
+	})
+	c.Specify("Matchers which are created by a function are named after the function", func() {
+		recorder := new(argumentsReporter)
+		runSpec(func(c Context) {
+			c.Expect(1.0, IsWithin(0.1), 2.0)
+		}).Report(recorder)
+		c.Expect(recorder.errors[0].MatcherName).Equals("IsWithin")
+	})
+	c.Specify("A negated matcher is named after Not", func() {
+		recorder := new(argumentsReporter)
+		runSpec(func(c Context) {
+			c.Expect(1, Not(Equals), 1)
+		}).Report(recorder)
+		c.Expect(recorder.errors[0].MatcherName).Equals("Not")
+	})
+	c.Specify("Errors which do not come from a matcher have no matcher name", func() {
+		recorder := new(argumentsReporter)
+		runSpec(func(c Context) {
+			panic("boom")
+		}).Report(recorder)
+		c.Expect(recorder.errors[0].MatcherName).Equals("")
+	})
+
+	c.Specify("When an expectation fails inside a helper function", func() {
+		spec := func(c Context) {
+			c.Specify("Child", func() {
+				expectEqualsInHelper(c, 1, 2)
+			})
+		}
+
+		c.Specify("then the stack trace goes through the helper function to the spec", func() {
+			stacktrace := stackTraceOfError(runSpec(spec))
+			c.Expect(len(stacktrace)).Equals(3)
+			c.Expect(stacktrace[0].Name()).Equals(fmt.Sprintf("%v.expectEqualsInHelper", pkgPath))
+			for _, loc := range stacktrace {
+				c.Expect(loc.FileName()).Equals("expectations_test.go")
+			}
+		})
+		c.Specify("then the GoSpec-internal frames are excluded", func() {
+			for _, loc := range stackTraceOfError(runSpec(spec)) {
+				c.Expect(strings.HasSuffix(loc.FileName(), "_test.go")).IsTrue()
+			}
+		})
+		c.Specify("then the internal frames are included when full stack traces are shown", func() {
+			r := NewRunner()
+			r.ShowFullStackTraces()
+			r.AddNamedSpec("RootSpec", spec)
+			r.Run()
+			stacktrace := stackTraceOfError(r.Results())
+			c.Expect(stacktrace[0].Name()).Equals(fmt.Sprintf("%v.expectEqualsInHelper", pkgPath))
+			files := []string{}
+			for _, loc := range stacktrace {
+				files = append(files, loc.FileName())
+			}
+			c.Expect(strings.Contains(strings.Join(files, ","), "context.go")).IsTrue()
+		})
+	})
+}
+
+func expectPositiveInHelper(c Context, n int) {
+	c.SkipCallerFrames(1).Expect(n, IsPositive)
+}
+
+func fluentExpectPositiveInHelper(c Context, n int) {
+	c.SkipCallerFrames(1).ExpectThat(n).To(IsPositive)
+}
+
+func assumePositiveInNestedHelper(c Context, n int) {
+	assumePositiveInHelper(c.SkipCallerFrames(1), n)
+}
+
+func assumePositiveInHelper(c Context, n int) {
+	c.SkipCallerFrames(1).Assume(n, IsPositive)
+}
+
+func expectEqualsInHelper(c Context, actual interface{}, expected interface{}) {
+	c.Expect(actual, Equals, expected)
+}
+
+// Stack trace of the first error in any of the specs.
+func stackTraceOfError(results *ResultCollector) []*Location {
+	var stacktrace []*Location
+	results.visitAll(func(spec *specResult) {
+		if stacktrace == nil && spec.errors.Len() > 0 {
+			stacktrace = spec.errors.Front().Value.(*Error).StackTrace
+		}
+	})
+	return stacktrace
 }
 
 func fileOfError(results *ResultCollector) string {
@@ -75,3 +295,13 @@ func fileOfError(results *ResultCollector) string {
 	}
 	return file
 }
+
+func messagesOfErrors(results *ResultCollector) []string {
+	messages := []string{}
+	for spec := range results.sortedRoots() {
+		for e := spec.errors.Front(); e != nil; e = e.Next() {
+			messages = append(messages, e.Value.(*Error).Message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,58 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Selects the specs by their full names. The names of the nested specs are
+// known only after their parents have been executed, so a spec which does not
+// match is executed if one of its children might match. Only when the pattern
+// is anchored to the beginning of the name is it possible to rule that out.
+type specFilter struct {
+	pattern  *regexp.Regexp
+	prefix   string // every matching name begins with this, if anchored is true
+	anchored bool
+}
+
+func newSpecFilter(pattern string) (*specFilter, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	prefix, _ := re.LiteralPrefix()
+	anchored := prog.StartCond()&syntax.EmptyBeginText != 0
+	return &specFilter{re, prefix, anchored}, nil
+}
+
+// The spec matches if its full name or the full name of one of its parents
+// matches the pattern.
+func (this *specFilter) matches(spec *specRun) bool {
+	if spec == nil {
+		return false
+	}
+	return this.pattern.MatchString(spec.fullName()) || this.matches(spec.parent)
+}
+
+// Returns false only when none of the spec's descendants can match,
+// because their full names begin with the full name of the spec.
+func (this *specFilter) mayMatchChildrenOf(spec *specRun) bool {
+	if !this.anchored {
+		return true
+	}
+	name := spec.fullName()
+	return strings.HasPrefix(name, this.prefix) || strings.HasPrefix(this.prefix, name+" ")
+}
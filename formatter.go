@@ -0,0 +1,128 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var formatters = struct {
+	sync.RWMutex
+	byType map[string]func(v interface{}) string
+}{byType: make(map[string]func(v interface{}) string)}
+
+// Registers a function for formatting the values of a type in the failure
+// messages, instead of the default `%v` format. The type name is the same as
+// printed by `%T`, for example "[]byte" or "time.Time". Registering a nil
+// function removes the formatter of the type. Example:
+//     gospec.RegisterFormatter("[]byte", func(v interface{}) string {
+//         return hex.EncodeToString(v.([]byte))
+//     })
+func RegisterFormatter(typeName string, fn func(v interface{}) string) {
+	typeName = canonicalTypeName(typeName)
+	formatters.Lock()
+	defer formatters.Unlock()
+	if fn == nil {
+		delete(formatters.byType, typeName)
+	} else {
+		formatters.byType[typeName] = fn
+	}
+}
+
+var byteAndRune = regexp.MustCompile(`\b(byte|rune)\b`)
+
+// The reflection uses the names of the types which byte and rune are aliases of.
+func canonicalTypeName(typeName string) string {
+	return byteAndRune.ReplaceAllStringFunc(typeName, func(alias string) string {
+		if alias == "byte" {
+			return "uint8"
+		}
+		return "int32"
+	})
+}
+
+func formatterOf(value interface{}) func(v interface{}) string {
+	if value == nil {
+		return nil
+	}
+	formatters.RLock()
+	defer formatters.RUnlock()
+	return formatters.byType[reflect.TypeOf(value).String()]
+}
+
+// Replaces the values which have a registered formatter with a wrapper
+// which uses the formatter. The `%T` and `%p` verbs are handled by fmt before
+// the wrapper is called, so their arguments are not replaced. If the format
+// uses explicit argument indexes, no arguments are replaced.
+func withFormatters(format string, args []interface{}) []interface{} {
+	result := make([]interface{}, len(args))
+	copy(result, args)
+	arg := 0
+	for i := 0; i < len(format) && arg < len(args); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*", format[i]) >= 0; i++ {
+			if format[i] == '*' {
+				arg++
+			}
+		}
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		if format[i] == '[' {
+			return args
+		}
+		if format[i] != 'T' && format[i] != 'p' && arg < len(args) {
+			result[arg] = withFormatter(args[arg])
+		}
+		arg++
+	}
+	return result
+}
+
+func withFormatter(value interface{}) interface{} {
+	if format := formatterOf(value); format != nil {
+		return formattedValue{value, format}
+	}
+	return value
+}
+
+// Uses the formatter for the `%v` and `%s` verbs. The other verbs,
+// such as `%#v` and `%T`, are formatted the same way as without it.
+type formattedValue struct {
+	value  interface{}
+	format func(v interface{}) string
+}
+
+func (this formattedValue) Format(f fmt.State, verb rune) {
+	if (verb == 'v' && !f.Flag('#')) || verb == 's' {
+		io.WriteString(f, this.format(this.value))
+	} else {
+		fmt.Fprintf(f, formatDirective(f, verb), this.value)
+	}
+}
+
+func formatDirective(f fmt.State, verb rune) string {
+	directive := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive += string(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive += strconv.Itoa(width)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive += "." + strconv.Itoa(precision)
+	}
+	return directive + string(verb)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+)
+
+func FormatterSpec(c nanospec.Context) {
+	RegisterFormatter("[]byte", func(v interface{}) string {
+		return "0x" + hex.EncodeToString(v.([]byte))
+	})
+	defer RegisterFormatter("[]byte", nil)
+	bytes := []byte{0xca, 0xfe}
+
+	c.Specify("Registered formatters are used for the values in messages", func() {
+		c.Expect(Errorf("equals “%v”", bytes).Error()).Equals("equals “0xcafe”")
+		c.Expect(Errorf("equals “%s”", bytes).Error()).Equals("equals “0xcafe”")
+		c.Expect(Errorf("%*d%% %v", 3, 7, bytes).Error()).Equals("  7% 0xcafe")
+	})
+	c.Specify("Registered formatters are used for the actual values of failures", func() {
+		recorder := new(argumentsReporter)
+		runSpec(func(c Context) {
+			c.Expect(bytes, HasLength, 3)
+		}).Report(recorder)
+		c.Expect(recorder.errors[0].Actual).Equals("0xcafe")
+	})
+	c.Specify("The other verbs are formatted the same way as without a formatter", func() {
+		c.Expect(Errorf("%#v", bytes).Error()).Equals(fmt.Sprintf("%#v", bytes))
+		c.Expect(Errorf("%T", bytes).Error()).Equals("[]uint8")
+		c.Expect(Errorf("%6x", bytes).Error()).Equals(fmt.Sprintf("%6x", bytes))
+	})
+	c.Specify("Values of other types are not affected", func() {
+		c.Expect(Errorf("%v", []int{1, 2}).Error()).Equals("[1 2]")
+		c.Expect(Errorf("%v", nil).Error()).Equals("<nil>")
+	})
+	c.Specify("Formatters can be removed", func() {
+		RegisterFormatter("[]byte", nil)
+		c.Expect(Errorf("%v", bytes).Error()).Equals("[202 254]")
+	})
+}
@@ -6,7 +6,9 @@ package gospec
 
 import (
 	"reflect"
+	"regexp"
 	"runtime"
+	"strings"
 )
 
 const unknownFunction = "<unknown function>"
@@ -18,6 +20,29 @@ func functionName(function interface{}) string {
 	return unknownFunction
 }
 
+// Name of the function without the package path, for example "Equals"
+// instead of "github.com/orfjackal/gospec/src/gospec.Equals".
+func shortFunctionName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Suffix which the compiler adds to the names of closures, for example
+// ".func1", ".func1.2" for nested closures or ".1" for inlined closures.
+var closureSuffix = regexp.MustCompile(`(\.func\d+|\.\d+)+$`)
+
+// Name of a matcher without the package path. A matcher which is created by
+// a function such as IsWithin or Not is a closure, so it is named after the
+// function which created it, for example "IsWithin" instead of "IsWithin.func1".
+func matcherName(matcher interface{}) string {
+	return closureSuffix.ReplaceAllString(shortFunctionName(functionName(matcher)), "")
+}
+
 func functionToFunc(function interface{}) *runtime.Func {
 	fval := reflect.ValueOf(function)
 	return runtime.FuncForPC(fval.Pointer())
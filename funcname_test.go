@@ -22,6 +22,18 @@ func FuncNameSpec(c nanospec.Context) {
 		// since weekly.2012-01-15 even anonymous functions have a name
 		c.Expect(name).Satisfies(strings.HasPrefix(name, fmt.Sprintf("%v._func_", pkgPath)))
 	})
+	c.Specify("The package path can be removed from the name of a function", func() {
+		c.Expect(shortFunctionName(functionName(dummyFunction))).Equals("dummyFunction")
+		c.Expect(shortFunctionName("example.com/pkg.Type.Method")).Equals("Type.Method")
+		c.Expect(shortFunctionName("main.main")).Equals("main")
+	})
+	c.Specify("The name of a matcher does not include the suffix of a closure", func() {
+		c.Expect(matcherName(Equals)).Equals("Equals")
+		c.Expect(matcherName(IsWithin(0.1))).Equals("IsWithin")
+		c.Expect(matcherName(Not(Equals))).Equals("Not")
+		c.Expect(closureSuffix.ReplaceAllString("Outer.func1.2", "")).Equals("Outer")
+		c.Expect(closureSuffix.ReplaceAllString("Outer.1", "")).Equals("Outer")
+	})
 }
 
 func dummyFunction() {
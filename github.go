@@ -0,0 +1,92 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitHubActionsReporter writes the failures as GitHub Actions workflow
+// commands, so that they are shown as annotations next to the code which
+// made the failing expectation. Only the failing specs are reported.
+type GitHubActionsReporter struct {
+	out  io.Writer
+	path []string
+}
+
+func NewGitHubActionsReporter(out io.Writer) *GitHubActionsReporter {
+	return &GitHubActionsReporter{out, []string{}}
+}
+
+// Tells whether the process is being run by GitHub Actions.
+func isGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+func (this *GitHubActionsReporter) SpecStarted(nestingLevel int, name string) {
+	this.path = append(this.path[:nestingLevel], name)
+}
+
+func (this *GitHubActionsReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+}
+
+func (this *GitHubActionsReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	title := strings.Join(this.path, " / ")
+	for _, error := range errors {
+		properties := ""
+		if len(error.StackTrace) > 0 {
+			loc := error.StackTrace[0]
+			properties += fmt.Sprintf("file=%v,line=%v,col=1,", gitHubProperty(gitHubFilePath(loc.File())), loc.Line())
+		}
+		properties += "title=" + gitHubProperty(title)
+		message := strings.TrimSuffix(formatErrorMessage(error), "\n")
+		fmt.Fprintf(this.out, "::error %v::%v\n", properties, gitHubData(message))
+	}
+}
+
+func (this *GitHubActionsReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+}
+
+func (this *GitHubActionsReporter) SpecPending(nestingLevel int, name string) {
+}
+
+func (this *GitHubActionsReporter) SuiteFinished(stats Stats) {
+}
+
+// GitHub expects the file paths to be relative to the repository.
+func gitHubFilePath(file string) string {
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" {
+		return file
+	}
+	relative, err := filepath.Rel(workspace, file)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return file
+	}
+	return filepath.ToSlash(relative)
+}
+
+func gitHubData(s string) string {
+	return strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	).Replace(s)
+}
+
+func gitHubProperty(s string) string {
+	return strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	).Replace(s)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bytes"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+)
+
+func GitHubActionsSpec(c nanospec.Context) {
+	trim := strings.TrimSpace
+	out := new(bytes.Buffer)
+	reporter := NewGitHubActionsReporter(out)
+
+	c.Specify("Failures are reported as error annotations", func() {
+		location := &Location{"gospec.SomeSpec", "/path/to/some_test.go", 42}
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reportSpec(reporter, 1, "Failing", []*Error{newError(ExpectFailed, "equals “20”", "10", []*Location{location})}, 0)
+		reporter.SuiteFinished(Stats{PassCount: 0, FailCount: 2})
+		c.Expect(trim(out.String())).Equals(
+			"::error file=/path/to/some_test.go,line=42,col=1,title=RootSpec / Failing::*** Expected: equals “20”%0A         got: “10”")
+	})
+
+	c.Specify("Passing specs are not reported", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reporter.SuiteFinished(Stats{PassCount: 1, FailCount: 0})
+		c.Expect(out.String()).Equals("")
+	})
+
+	c.Specify("Errors without a location are reported without a file", func() {
+		reportSpec(reporter, 0, "RootSpec", someError, 0)
+		c.Expect(trim(out.String())).Equals("::error title=RootSpec::*** some error")
+	})
+
+	c.Specify("Special characters are escaped", func() {
+		c.Expect(gitHubData("100% done\r\nok: yes")).Equals("100%25 done%0D%0Aok: yes")
+		c.Expect(gitHubProperty("a: b, 100%\n")).Equals("a%3A b%2C 100%25%0A")
+	})
+
+	c.Specify("File paths are relative to the GitHub workspace", func() {
+		withEnv("GITHUB_WORKSPACE", "/home/runner/work/project", func() {
+			c.Expect(gitHubFilePath("/home/runner/work/project/pkg/some_test.go")).Equals("pkg/some_test.go")
+			c.Expect(gitHubFilePath("/usr/lib/go/src/testing/testing.go")).Equals("/usr/lib/go/src/testing/testing.go")
+		})
+	})
+
+	c.Specify("GitHub Actions is detected from the environment", func() {
+		withEnv("GITHUB_ACTIONS", "true", func() {
+			c.Expect(isGitHubActions()).IsTrue()
+		})
+		withEnv("GITHUB_ACTIONS", "", func() {
+			c.Expect(isGitHubActions()).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,217 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+)
+
+func HooksSpec(c nanospec.Context) {
+	executed := []string{}
+	log := func(name string) func() {
+		return func() {
+			executed = append(executed, name)
+		}
+	}
+	run := func(spec func(Context)) *ResultCollector {
+		r := NewRunner()
+		r.AddNamedSpec("RootSpec", spec)
+		r.RunParallel(1)
+		return r.Results()
+	}
+
+	c.Specify("BeforeEach and AfterEach hooks are executed around every child spec", func() {
+		run(func(c Context) {
+			c.BeforeEach(log("before"))
+			c.AfterEach(log("after"))
+			c.Specify("a", log("a"))
+			c.Specify("b", log("b"))
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("before,a,after,before,b,after")
+	})
+
+	c.Specify("BeforeEach hooks are executed in registration order and AfterEach hooks in reverse order", func() {
+		run(func(c Context) {
+			c.BeforeEach(log("before 1"))
+			c.BeforeEach(log("before 2"))
+			c.AfterEach(log("after 1"))
+			c.AfterEach(log("after 2"))
+			c.Specify("a", log("a"))
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("before 1,before 2,a,after 2,after 1")
+	})
+
+	c.Specify("The hooks of nested specs are executed inside the hooks of their parents", func() {
+		run(func(c Context) {
+			c.BeforeEach(log("outer before"))
+			c.AfterEach(log("outer after"))
+			c.Specify("a", func() {
+				c.BeforeEach(log("inner before"))
+				c.AfterEach(log("inner after"))
+				c.Specify("aa", log("aa"))
+			})
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("outer before,inner before,aa,inner after,outer after")
+	})
+
+	c.Specify("AfterEach hooks are executed even when the spec fails", func() {
+		results := run(func(c Context) {
+			c.AfterEach(log("after"))
+			c.Specify("a", func() {
+				panic("boom")
+			})
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("after")
+		c.Expect(results.FailCount()).Equals(1)
+	})
+
+	c.Specify("When a BeforeEach hook panics", func() {
+		results := run(func(c Context) {
+			c.BeforeEach(func() {
+				panic("boom")
+			})
+			c.BeforeEach(log("before"))
+			c.AfterEach(log("after"))
+			c.Specify("a", log("a"))
+		})
+
+		c.Specify("then the spec fails without being executed", func() {
+			c.Expect(results.FailCount()).Equals(1)
+			c.Expect(strings.Join(executed, ",")).Equals("after")
+		})
+		c.Specify("then the panic is reported", func() {
+			reporter := new(argumentsReporter)
+			results.Report(reporter)
+			c.Expect(reporter.errors[0].Message).Equals("panic: boom")
+		})
+	})
+
+	c.Specify("A panic in an AfterEach hook fails the spec", func() {
+		results := run(func(c Context) {
+			c.AfterEach(log("after 1"))
+			c.AfterEach(func() {
+				panic("boom")
+			})
+			c.Specify("a", log("a"))
+		})
+		c.Expect(results.FailCount()).Equals(1)
+		c.Expect(strings.Join(executed, ",")).Equals("a,after 1")
+	})
+
+	c.Specify("BeforeAll and AfterAll hooks are executed only once", func() {
+		run(func(c Context) {
+			c.BeforeAll(log("before all"))
+			c.AfterAll(log("after all"))
+			c.Specify("a", log("a"))
+			c.Specify("b", log("b"))
+			c.Specify("c", log("c"))
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("before all,a,c,b,after all")
+	})
+
+	c.Specify("BeforeAll hooks are executed in registration order and AfterAll hooks in reverse order", func() {
+		run(func(c Context) {
+			c.BeforeAll(log("before 1"))
+			c.BeforeAll(log("before 2"))
+			c.AfterAll(log("after 1"))
+			c.AfterAll(log("after 2"))
+			c.Specify("a", log("a"))
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("before 1,before 2,a,after 2,after 1")
+	})
+
+	c.Specify("The outer BeforeAll hooks are executed before the inner hooks", func() {
+		run(func(c Context) {
+			c.BeforeAll(log("outer before"))
+			c.AfterAll(log("outer after"))
+			c.Specify("a", func() {
+				c.BeforeAll(log("inner before"))
+				c.AfterAll(log("inner after"))
+				c.Specify("aa", log("aa"))
+				c.Specify("ab", log("ab"))
+			})
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("outer before,inner before,aa,ab,inner after,outer after")
+	})
+
+	c.Specify("AfterAll hooks are executed even when the specs fail", func() {
+		results := run(func(c Context) {
+			c.AfterAll(log("after all"))
+			c.Specify("a", func() {
+				panic("boom")
+			})
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("after all")
+		c.Expect(results.FailCount()).Equals(1)
+	})
+
+	c.Specify("When a BeforeAll hook panics, the children fail without being executed", func() {
+		results := run(func(c Context) {
+			c.BeforeAll(func() {
+				panic("boom")
+			})
+			c.Specify("a", log("a"))
+			c.Specify("b", log("b"))
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("")
+		c.Expect(results.FailCount()).Equals(1)
+		c.Expect(results.TotalCount()).Equals(1)
+	})
+
+	c.Specify("A panic in an AfterAll hook fails the spec which registered it", func() {
+		results := run(func(c Context) {
+			c.Specify("a", func() {
+				c.AfterAll(func() {
+					panic("boom")
+				})
+			})
+		})
+		reporter := new(argumentsReporter)
+		results.Report(reporter)
+		c.Expect(results.FailCount()).Equals(1)
+		c.Expect(reporter.errors[0].Message).Equals("panic: boom")
+	})
+
+	c.Specify("Cleanups are executed in reverse order after the spec", func() {
+		run(func(c Context) {
+			c.AfterEach(log("after each"))
+			c.Specify("a", func() {
+				c.DeferCleanup(log("cleanup 1"))
+				c.DeferCleanup(log("cleanup 2"))
+				executed = append(executed, "a")
+			})
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("a,cleanup 2,cleanup 1,after each")
+	})
+
+	c.Specify("Cleanups are executed after the children of the spec", func() {
+		run(func(c Context) {
+			c.DeferCleanup(log("cleanup"))
+			c.Specify("a", log("a"))
+		})
+		c.Expect(strings.Join(executed, ",")).Equals("a,cleanup")
+	})
+
+	c.Specify("When a cleanup panics", func() {
+		results := run(func(c Context) {
+			c.DeferCleanup(log("cleanup 1"))
+			c.DeferCleanup(func() {
+				panic("boom")
+			})
+			c.DeferCleanup(log("cleanup 3"))
+		})
+
+		c.Specify("then the other cleanups are still executed", func() {
+			c.Expect(strings.Join(executed, ",")).Equals("cleanup 3,cleanup 1")
+		})
+		c.Specify("then the spec fails", func() {
+			reporter := new(argumentsReporter)
+			results.Report(reporter)
+			c.Expect(results.FailCount()).Equals(1)
+			c.Expect(reporter.errors[0].Message).Equals("panic: boom")
+		})
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JSONReporter writes the spec results as line-delimited JSON, one object per
+// spec, so that the output can be processed one line at a time, for example
+// with jq. Every object is flushed when it is written, so when the reporter
+// is given to Runner.ReportTo, the results of the finished specs are
+// available before the whole suite has been executed.
+type JSONReporter struct {
+	out     io.Writer
+	encoder *json.Encoder
+	path    []string
+}
+
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	return &JSONReporter{out, encoder, []string{}}
+}
+
+type jsonSpecResult struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	DurationNs     int64  `json:"duration_ns"`
+	FailureMessage string `json:"failure_message"`
+	Location       string `json:"location"`
+	Matcher        string `json:"matcher,omitempty"`
+}
+
+func (this *JSONReporter) SpecStarted(nestingLevel int, name string) {
+	this.path = append(this.path[:nestingLevel], name)
+}
+
+func (this *JSONReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.write(jsonSpecResult{
+		Name:       strings.Join(this.path, " / "),
+		Status:     "pass",
+		DurationNs: duration.Nanoseconds(),
+	})
+}
+
+func (this *JSONReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	messages := make([]string, len(errors))
+	for i, error := range errors {
+		messages[i] = strings.TrimSuffix(formatErrorMessage(error), "\n")
+	}
+	location := ""
+	if trace := errors[0].StackTrace; len(trace) > 0 {
+		location = fmt.Sprintf("%v:%v", trace[0].File(), trace[0].Line())
+	}
+	this.write(jsonSpecResult{
+		Name:           strings.Join(this.path, " / "),
+		Status:         "fail",
+		DurationNs:     duration.Nanoseconds(),
+		FailureMessage: strings.Join(messages, "\n"),
+		Location:       location,
+		Matcher:        errors[0].MatcherName,
+	})
+}
+
+func (this *JSONReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	this.write(jsonSpecResult{
+		Name:           strings.Join(this.path, " / "),
+		Status:         "skip",
+		FailureMessage: reason,
+	})
+}
+
+func (this *JSONReporter) SpecPending(nestingLevel int, name string) {
+	this.write(jsonSpecResult{
+		Name:   strings.Join(this.path, " / "),
+		Status: "pending",
+	})
+}
+
+func (this *JSONReporter) SuiteFinished(stats Stats) {
+}
+
+func (this *JSONReporter) write(result jsonSpecResult) {
+	if err := this.encoder.Encode(result); err != nil {
+		panic(err)
+	}
+	flushIfBuffered(this.out)
+}
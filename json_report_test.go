@@ -0,0 +1,110 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"io"
+	"strings"
+	"time"
+)
+
+func JSONReportSpec(c nanospec.Context) {
+	out := new(bytes.Buffer)
+	reporter := NewJSONReporter(out)
+
+	decodeLines := func() []map[string]interface{} {
+		results := []map[string]interface{}{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var result map[string]interface{}
+			err := json.Unmarshal([]byte(line), &result)
+			c.Expect(err).Equals(nil)
+			results = append(results, result)
+		}
+		return results
+	}
+
+	c.Specify("Passing specs", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 1500*time.Nanosecond)
+		reportSpec(reporter, 1, "Child", noErrors, 500*time.Nanosecond)
+		results := decodeLines()
+
+		c.Expect(len(results)).Equals(2)
+		c.Expect(results[1]["name"]).Equals("RootSpec / Child")
+		c.Expect(results[1]["status"]).Equals("pass")
+		c.Expect(results[1]["duration_ns"]).Equals(float64(500))
+		c.Expect(results[1]["failure_message"]).Equals("")
+		c.Expect(results[1]["location"]).Equals("")
+		c.Expect(results[1]["matcher"]).Equals(nil)
+	})
+
+	c.Specify("Failing specs", func() {
+		location := &Location{"gospec.SomeSpec", "/path/to/some_test.go", 42}
+		reportSpec(reporter, 0, "RootSpec", []*Error{
+			newError(ExpectFailed, "equals “<20>”", "10", []*Location{location}),
+			newError(OtherError, "panic: boom", "", []*Location{}),
+		}, time.Millisecond)
+		results := decodeLines()
+
+		c.Expect(results[0]["name"]).Equals("RootSpec")
+		c.Expect(results[0]["status"]).Equals("fail")
+		c.Expect(results[0]["duration_ns"]).Equals(float64(1000000))
+		c.Expect(results[0]["failure_message"]).Equals(
+			"*** Expected: equals “<20>”\n" +
+				"         got: “10”\n" +
+				"*** panic: boom")
+		c.Expect(results[0]["location"]).Equals("/path/to/some_test.go:42")
+	})
+
+	c.Specify("Every spec is written on its own line when it is reported", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		c.Expect(strings.Count(out.String(), "\n")).Equals(1)
+		reportSpec(reporter, 1, "Child", noErrors, 0)
+		c.Expect(strings.Count(out.String(), "\n")).Equals(2)
+	})
+
+	c.Specify("The finished specs are written before Run returns", func() {
+		whileRunning, afterRun := reportWhileRunning(func(out io.Writer) Reporter {
+			return NewJSONReporter(out)
+		}, func(output string) bool {
+			return strings.Count(output, "\n") == 2
+		})
+
+		c.Expect(strings.Count(whileRunning, "\n")).Equals(2)
+		c.Expect(strings.Contains(whileRunning, `"name":"A",`)).IsTrue()
+		c.Expect(strings.Contains(whileRunning, `"name":"A / Child"`)).IsTrue()
+		c.Expect(strings.Count(afterRun, "\n")).Equals(3)
+		c.Expect(strings.Contains(afterRun, `"name":"B"`)).IsTrue()
+	})
+
+	c.Specify("Results of a real run", func() {
+		results := runSpec(func(c Context) {
+			c.Expect(1, Equals, 2)
+		})
+		results.Report(reporter)
+		c.Expect(strings.Contains(out.String(), `"location":"`)).IsTrue()
+		c.Expect(strings.Contains(out.String(), `json_report_test.go:`)).IsTrue()
+		c.Expect(strings.Contains(out.String(), `"matcher":"Equals"`)).IsTrue()
+	})
+
+	c.Specify("Skipped specs", func() {
+		reporter.SpecStarted(0, "RootSpec")
+		reporter.SpecSkipped(0, "RootSpec", "not supported")
+		results := decodeLines()
+
+		c.Expect(results[0]["status"]).Equals("skip")
+		c.Expect(results[0]["failure_message"]).Equals("not supported")
+	})
+
+	c.Specify("Pending specs", func() {
+		reporter.SpecStarted(0, "RootSpec")
+		reporter.SpecPending(0, "RootSpec")
+		results := decodeLines()
+
+		c.Expect(results[0]["status"]).Equals("pending")
+	})
+}
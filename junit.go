@@ -0,0 +1,119 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JUnitReporter writes the spec results as a JUnit XML document, which is
+// understood by most continuous integration servers. Every spec is reported
+// as its own test case. The document is written when the suite has finished.
+type JUnitReporter struct {
+	out   io.Writer
+	path  []string
+	suite junitTestSuite
+}
+
+func NewJUnitReporter(out io.Writer) *JUnitReporter {
+	return &JUnitReporter{
+		out:   out,
+		path:  []string{},
+		suite: junitTestSuite{Name: "gospec", TestCases: []junitTestCase{}},
+	}
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Time      string         `xml:"time,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Skipped   *junitSkipped  `xml:"skipped"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (this *JUnitReporter) SpecStarted(nestingLevel int, name string) {
+	this.path = append(this.path[:nestingLevel], name)
+}
+
+func (this *JUnitReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.addTestCase(duration)
+}
+
+func (this *JUnitReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	testCase := this.addTestCase(duration)
+	for _, error := range errors {
+		testCase.Failures = append(testCase.Failures, junitFailure{
+			Message: error.Message,
+			Type:    errorTypeName(error.Type),
+			Text:    junitFailureText(error),
+		})
+	}
+}
+
+func (this *JUnitReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	testCase := this.addTestCase(0)
+	testCase.Skipped = &junitSkipped{Message: reason}
+}
+
+func (this *JUnitReporter) SpecPending(nestingLevel int, name string) {
+	this.SpecSkipped(nestingLevel, name, "pending")
+}
+
+func (this *JUnitReporter) addTestCase(duration time.Duration) *junitTestCase {
+	this.suite.TestCases = append(this.suite.TestCases, junitTestCase{
+		ClassName: this.path[0],
+		Name:      specPathName(this.path),
+		Time:      junitSeconds(duration),
+	})
+	return &this.suite.TestCases[len(this.suite.TestCases)-1]
+}
+
+func (this *JUnitReporter) SuiteFinished(stats Stats) {
+	this.suite.Tests = stats.TotalCount()
+	this.suite.Failures = stats.FailCount
+	this.suite.Skipped = stats.SkipCount + stats.PendingCount
+	this.suite.Time = junitSeconds(stats.Duration)
+
+	bytes, err := xml.MarshalIndent(this.suite, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(this.out, "%v%s\n", xml.Header, bytes)
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func junitFailureText(e *Error) string {
+	s := formatErrorMessage(e)
+	for _, loc := range e.StackTrace {
+		s += fmt.Sprintf("    at %v\n", loc)
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+	"time"
+)
+
+func JUnitSpec(c nanospec.Context) {
+	out := new(bytes.Buffer)
+	reporter := NewJUnitReporter(out)
+
+	c.Specify("When reporting specs", func() {
+		results := runSpec(func(c Context) {
+			c.Specify("Passing", func() {
+				c.Specify("Nested", func() {})
+			})
+			c.Specify("Failing", func() {
+				c.Expect(10, Equals, 20)
+				c.Expect("<b>", Equals, "</b>")
+			})
+		})
+		results.Report(reporter)
+
+		var suite junitTestSuite
+		err := xml.Unmarshal(out.Bytes(), &suite)
+		c.Expect(err).Equals(nil)
+
+		c.Specify("then the output is a well-formed XML document", func() {
+			c.Expect(strings.HasPrefix(out.String(), `<?xml version="1.0" encoding="UTF-8"?>`)).IsTrue()
+		})
+		c.Specify("then the test suite has the spec counts", func() {
+			c.Expect(suite.Name).Equals("gospec")
+			c.Expect(suite.Tests).Equals(4)
+			c.Expect(suite.Failures).Equals(1)
+		})
+		c.Specify("then every spec is a test case named by its path", func() {
+			c.Expect(len(suite.TestCases)).Equals(4)
+			c.Expect(suite.TestCases[0].ClassName).Equals("RootSpec")
+			c.Expect(suite.TestCases[0].Name).Equals("RootSpec")
+			c.Expect(suite.TestCases[1].Name).Equals("Passing")
+			c.Expect(suite.TestCases[2].Name).Equals("Passing / Nested")
+			c.Expect(suite.TestCases[3].ClassName).Equals("RootSpec")
+			c.Expect(suite.TestCases[3].Name).Equals("Failing")
+		})
+		c.Specify("then failed expectations are reported as failures", func() {
+			c.Expect(len(suite.TestCases[0].Failures)).Equals(0)
+			failures := suite.TestCases[3].Failures
+			c.Expect(len(failures)).Equals(2)
+			c.Expect(failures[0].Message).Equals("equals “20”")
+			c.Expect(failures[0].Type).Equals("expectation")
+			c.Expect(strings.Contains(failures[0].Text, "got: “10”")).IsTrue()
+			c.Expect(strings.Contains(failures[0].Text, "at junit_test.go:")).IsTrue()
+		})
+		c.Specify("then special characters are escaped", func() {
+			c.Expect(strings.Contains(out.String(), "</b>”")).IsFalse()
+			c.Expect(suite.TestCases[3].Failures[1].Message).Equals("equals “</b>”")
+		})
+	})
+
+	c.Specify("The elapsed time is reported in seconds", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 1500*time.Millisecond)
+		reportSpec(reporter, 1, "Child", noErrors, 250*time.Millisecond)
+		reporter.SuiteFinished(Stats{PassCount: 2, Duration: 1500 * time.Millisecond})
+
+		var suite junitTestSuite
+		xml.Unmarshal(out.Bytes(), &suite)
+		c.Expect(suite.Time).Equals("1.500")
+		c.Expect(suite.TestCases[0].Time).Equals("1.500")
+		c.Expect(suite.TestCases[1].Time).Equals("0.250")
+	})
+
+	c.Specify("Skipped specs are reported with the reason", func() {
+		reporter.SpecStarted(0, "RootSpec")
+		reporter.SpecSkipped(0, "RootSpec", "not supported")
+		reporter.SuiteFinished(Stats{SkipCount: 1})
+
+		var suite junitTestSuite
+		xml.Unmarshal(out.Bytes(), &suite)
+		c.Expect(suite.Tests).Equals(1)
+		c.Expect(suite.Skipped).Equals(1)
+		c.Expect(suite.TestCases[0].Skipped.Message).Equals("not supported")
+	})
+}
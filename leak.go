@@ -0,0 +1,73 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// How long AssertNoGoroutineLeak waits for the goroutines to exit.
+const goroutineLeakTimeout = 500 * time.Millisecond
+
+// Executes the closure and fails the spec if it leaves goroutines running.
+// Because goroutines may take a moment to exit, the check is repeated until
+// the goroutines have exited or a timeout has passed. The stack traces of the
+// leaked goroutines are shown in the failure message. Goroutines started
+// concurrently by other specs are also seen as leaks, so the specs which use
+// this should not be executed in parallel with other specs. For example:
+//    r.AddSpec(WorkerSpec, Serial())
+func AssertNoGoroutineLeak(c Context, closure func()) {
+	before := goroutineStacks()
+	closure()
+	leaked := leakedGoroutines(before)
+	for deadline := time.Now().Add(goroutineLeakTimeout); len(leaked) > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		leaked = leakedGoroutines(before)
+	}
+	c.SkipCallerFrames(1).Expect(leaked, hasNoLeakedGoroutines)
+}
+
+func hasNoLeakedGoroutines(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	leaked := actual.([]string)
+	match = len(leaked) == 0
+	pos = newMessage(len(leaked), errorf1("no goroutines are leaked, but these were still running:\n\n%v", strings.Join(leaked, "\n\n")))
+	neg = newMessage(len(leaked), errorf0("goroutines are leaked"))
+	return
+}
+
+// Stack traces of the goroutines which were not running before.
+func leakedGoroutines(before map[string]string) []string {
+	leaked := []string{}
+	for id, stack := range goroutineStacks() {
+		if _, ok := before[id]; !ok {
+			leaked = append(leaked, stack)
+		}
+	}
+	return leaked
+}
+
+// Stack traces of all goroutines, by the goroutine ID.
+func goroutineStacks() map[string]string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		// The first line is like "goroutine 42 [running]:"
+		fields := strings.Fields(stack)
+		if len(fields) >= 2 && fields[0] == "goroutine" {
+			stacks[fields[1]] = strings.TrimSpace(stack)
+		}
+	}
+	return stacks
+}
@@ -0,0 +1,55 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+	"time"
+)
+
+func GoroutineLeakSpec(c nanospec.Context) {
+
+	c.Specify("When the goroutines exit before the closure returns", func() {
+		results := runSpec(func(c Context) {
+			AssertNoGoroutineLeak(c, func() {
+				done := make(chan bool)
+				go func() { done <- true }()
+				<-done
+			})
+		})
+		c.Expect(results.FailCount()).Equals(0)
+	})
+
+	c.Specify("When the goroutines exit soon after the closure returns", func() {
+		results := runSpec(func(c Context) {
+			AssertNoGoroutineLeak(c, func() {
+				go time.Sleep(50 * time.Millisecond)
+			})
+		})
+		c.Expect(results.FailCount()).Equals(0)
+	})
+
+	c.Specify("When a goroutine keeps on running, the spec fails with its stack trace", func() {
+		release := make(chan bool)
+		defer close(release)
+		results := runSpec(func(c Context) {
+			AssertNoGoroutineLeak(c, func() {
+				go leakingGoroutine(release)
+			})
+		})
+
+		c.Expect(results.FailCount()).Equals(1)
+		c.Expect(fileOfError(results)).Equals("leak_test.go")
+		messages := messagesOfErrors(results)
+		c.Expect(len(messages)).Equals(1)
+		c.Expect(strings.Contains(messages[0], "no goroutines are leaked")).IsTrue()
+		c.Expect(strings.Contains(messages[0], "gospec.leakingGoroutine")).IsTrue()
+	})
+}
+
+func leakingGoroutine(release chan bool) {
+	<-release
+}
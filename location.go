@@ -8,6 +8,7 @@ import (
 	"fmt"
 	filepath "path"
 	"runtime"
+	"strings"
 )
 
 type Location struct {
@@ -24,36 +25,83 @@ func callerLocation() *Location {
 	return newLocation(2)
 }
 
+// The frames are found with runtime.CallersFrames, so that also the
+// functions which the compiler has inlined into their callers are counted.
 func newLocation(n int) *Location {
-	if pc, _, _, ok := runtime.Caller(n + 1); ok {
-		return locationForPC(pc)
+	callers := make([]uintptr, 1)
+	// Skip runtime.Callers, this function and the n callers
+	if runtime.Callers(n+2, callers) == 0 {
+		return nil
 	}
-	return nil
+	return locationForPC(callers[0])
 }
 
+// The program counter is one returned by runtime.Callers,
+// which is where the call will return to.
 func locationForPC(pc uintptr) *Location {
-	pc = pcOfWhereCallWasMade(pc)
-	f := runtime.FuncForPC(pc)
-	name := f.Name()
-	file, line := f.FileLine(pc)
-	return &Location{name, file, line}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return locationOfFrame(frame)
 }
 
-// Quoted from http://code.google.com/p/go/issues/detail?id=1100
-//   "It's a subtle thing, but runtime.Callers returns the return PCs
-//   going up the stack.  The return PCs are the PCs of the instruction
-//   that the call returns to, not the call itself.  The code that formats
-//   the traceback for a crash subtracts 1 from each PC before translating
-//   it to a line number.  The equivalent change in your code would be to
-//   call f.FileLine(pc - 1)."
-func pcOfWhereCallWasMade(pcOfWhereCallReturnsTo uintptr) uintptr {
-	return pcOfWhereCallReturnsTo - 1
+func locationOfFrame(frame runtime.Frame) *Location {
+	return &Location{frame.Function, frame.File, frame.Line}
+}
+
+// Stack trace of the code which called the caller of this function, up to
+// where the root spec was executed. Includes also the frames of GoSpec,
+// which can be removed with trimStackTrace.
+func callerStackTrace() []*Location {
+	callers := make([]uintptr, 32)
+	for {
+		// Skip runtime.Callers, this function and its caller
+		count := runtime.Callers(3, callers)
+		if count == len(callers) {
+			callers = make([]uintptr, len(callers)*2)
+		} else {
+			callers = callers[0:count]
+			break
+		}
+	}
+	return cutStackTraceAtOutermost(recoverOnPanic, asLocationArray(callers))
+}
+
+func cutStackTraceAtOutermost(cutpoint interface{}, stacktrace []*Location) []*Location {
+	name := functionName(cutpoint)
+	for i := len(stacktrace) - 1; i >= 0; i-- {
+		if stacktrace[i].name == name {
+			return stacktrace[0:i]
+		}
+	}
+	return stacktrace
+}
+
+// Removes the frames of GoSpec and the Go runtime, so that only
+// the frames of the specs and their helper functions are left.
+func trimStackTrace(stacktrace []*Location) []*Location {
+	result := make([]*Location, 0, len(stacktrace))
+	for _, loc := range stacktrace {
+		if !loc.isInternal() {
+			result = append(result, loc)
+		}
+	}
+	return result
+}
+
+var gospecSourceDir = filepath.Dir(currentLocation().File())
+
+func (this *Location) isInternal() bool {
+	if strings.HasPrefix(this.name, "runtime.") {
+		return true
+	}
+	return filepath.Dir(this.file) == gospecSourceDir &&
+		!strings.HasSuffix(this.file, "_test.go")
 }
 
 func (this *Location) Name() string     { return this.name }
 func (this *Location) File() string     { return this.file }
 func (this *Location) FileName() string { return filename(this.file) }
 func (this *Location) Line() int        { return this.line }
+func (this *Location) FuncName() string { return shortFunctionName(this.name) }
 
 func filename(path string) string {
 	_, file := filepath.Split(path)
@@ -69,3 +117,9 @@ func (this *Location) equals(that *Location) bool {
 func (this *Location) String() string {
 	return fmt.Sprintf("%v:%v", this.FileName(), this.Line())
 }
+
+// Same as String, but includes also the name of the function, in the format
+// used by compilers and IDEs, for example "stack_test.go:12: in StackSpec".
+func (this *Location) LongString() string {
+	return fmt.Sprintf("%v: in %v", this.String(), this.FuncName())
+}
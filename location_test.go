@@ -35,6 +35,15 @@ func LocationSpec(c nanospec.Context) {
 		loc := methodWhereLocationIsCalled()
 		c.Expect(loc.Name()).Equals(fmt.Sprintf("%v.methodWhereLocationIsCalled", pkgPath))
 	})
+	c.Specify("The name of the method can be shortened by removing the package path", func() {
+		loc := methodWhereLocationIsCalled()
+		c.Expect(loc.FuncName()).Equals("methodWhereLocationIsCalled")
+	})
+	c.Specify("The long format contains the file, line and method", func() {
+		loc := &Location{"example.com/pkg.SomeSpec.func1", "/path/to/some_test.go", 42}
+		c.Expect(loc.String()).Equals("some_test.go:42")
+		c.Expect(loc.LongString()).Equals("some_test.go:42: in SomeSpec.func1")
+	})
 	c.Specify("Calls to newLocation are synced with the helper methods", func() {
 		c.Expect(newLocation(0).Name()).Equals(currentLocation().Name())
 		c.Expect(newLocation(0).File()).Equals(currentLocation().File())
@@ -48,6 +57,18 @@ func LocationSpec(c nanospec.Context) {
 		c.Expect(loc.FileName()).Equals("location_test.go")
 		c.Expect(loc.Line()).Equals(expectedLine)
 	})
+	c.Specify("Functions which are inlined by the compiler have their own frames in stack traces", func() {
+		expectedLine := currentLocation().Line() + 1
+		stacktrace := stackTraceOfInlinedFunction()
+		c.Expect(stacktrace[0].FileName()).Equals("location_test.go")
+		c.Expect(stacktrace[0].Line()).Equals(expectedLine)
+		c.Expect(stacktrace[0].FuncName()).Equals("LocationSpec.func9")
+	})
+}
+
+// Small enough to be inlined, unless compiler optimizations are disabled.
+func stackTraceOfInlinedFunction() []*Location {
+	return callerStackTrace()
 }
 
 func methodWhereLocationIsCalled() *Location {
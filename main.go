@@ -6,12 +6,23 @@ package gospec
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"testing"
 )
 
+// Number of lines shown before and after a failure with -source-context.
+const sourceContextLines = 1
+
 var (
-	printAll = flag.Bool("print-all", false, "print also passing specs and not only failing (GoSpec)")
+	printAll    = flag.Bool("print-all", false, "print also passing specs and not only failing (GoSpec)")
+	color       = flag.String("color", "auto", "colorize the output: always, never or auto (GoSpec)")
+	randomOrder = flag.Bool("random", false, "execute the specs in random order (GoSpec)")
+	randomSeed  = flag.Int64("seed", 0, "seed for executing the specs in random order; implies -random (GoSpec)")
+	failFast    = flag.Bool("fail-fast", false, "stop executing the specs after the first failure (GoSpec)")
+	filter      = flag.String("filter", "", "run only the nested specs whose full name matches the regular expression (GoSpec)")
+	fullStacks  = flag.Bool("full-stacks", false, "show also the GoSpec-internal frames in stack traces (GoSpec)")
+	showSource  = flag.Bool("source-context", false, "show the source code around the failures (GoSpec)")
 )
 
 // Executes the specs which have been added to the Runner
@@ -20,7 +31,11 @@ var (
 // depending on whether any specs failed.
 func Main(runner *Runner) {
 	flag.Parse()
-	results := runAndPrint(runner)
+	results, err := runAndPrint(runner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	if results.FailCount() > 0 {
 		os.Exit(1)
 	} else {
@@ -36,23 +51,73 @@ func MainGoTest(runner *Runner, t *testing.T) {
 	// flag.Parse() has already been called in testing.Main() so
 	// we don't need to call it here.
 
-	results := runAndPrint(runner)
+	results, err := runAndPrint(runner)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if results.FailCount() > 0 {
 		t.Fail()
 	}
 }
 
-func runAndPrint(runner *Runner) *ResultCollector {
-	printer := NewPrinter(DefaultPrintFormat(os.Stdout))
+func runAndPrint(runner *Runner) (*ResultCollector, error) {
+	printer := NewPrinter(printFormatFor(*color, os.Stdout))
 	if *printAll {
 		printer.ShowAll()
 	} else {
 		printer.ShowOnlyFailing()
 	}
 	printer.ShowSummary()
+	if *showSource {
+		printer.ShowSourceContext(sourceContextLines)
+	}
 
+	reporters := []Reporter{printer}
+	if isTeamCity() {
+		reporters = append(reporters, NewTeamCityReporter(os.Stdout))
+	}
+	if isGitHubActions() {
+		reporters = append(reporters, NewGitHubActionsReporter(os.Stdout))
+	}
+
+	if *filter != "" {
+		if err := runner.FilterByName(*filter); err != nil {
+			return nil, fmt.Errorf("invalid -filter: %v", err)
+		}
+	}
+	if *failFast {
+		runner.FailFast()
+	}
+	if *fullStacks {
+		runner.ShowFullStackTraces()
+	}
+	if *randomOrder || *randomSeed != 0 {
+		seed := runner.RandomizeOrder(*randomSeed)
+		fmt.Printf("Running specs in random order (seed: %v)\n", seed)
+	}
+	reporter := NewMultiReporter(reporters...)
+	runner.ReportTo(reporter)
 	runner.Run()
-	results := runner.Results()
-	results.Visit(printer)
-	return results
+	if err := reporter.Err(); err != nil {
+		return nil, fmt.Errorf("reporting the results failed: %v", err)
+	}
+	return runner.Results(), nil
+}
+
+func printFormatFor(color string, out *os.File) PrintFormat {
+	switch color {
+	case "always":
+		return ColorPrintFormat(out)
+	case "never":
+		return PlainPrintFormat(out)
+	}
+	if isTerminal(out) {
+		return ColorPrintFormat(out)
+	}
+	return PlainPrintFormat(out)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
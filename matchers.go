@@ -6,49 +6,81 @@ package gospec
 
 import (
 	"container/list"
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
 type matcherAdapter struct {
-	location    *Location
-	log         errorLogger
-	matcherType ErrorType
+	stacktrace       []*Location
+	log              errorLogger
+	matcherType      ErrorType
+	captureOnFailure bool
+	skip             int
+	fullStacks       bool
 }
 
-func newMatcherAdapter(location *Location, log errorLogger, matcherType ErrorType) *matcherAdapter {
-	return &matcherAdapter{location, log, matcherType}
+func newMatcherAdapter(stacktrace []*Location, log errorLogger, matcherType ErrorType) *matcherAdapter {
+	if stacktrace == nil {
+		stacktrace = []*Location{}
+	}
+	return &matcherAdapter{stacktrace: stacktrace, log: log, matcherType: matcherType}
+}
+
+// Same as newMatcherAdapter, but the stack trace is captured only when
+// the expectation fails, because finding the stack trace is slow. It begins
+// from the caller of the Context method which made the expectation, after
+// skipping the given number of frames. Without full stack traces,
+// the GoSpec-internal frames are removed.
+func newCallerMatcherAdapter(skip int, fullStacks bool, log errorLogger, matcherType ErrorType) *matcherAdapter {
+	return &matcherAdapter{log: log, matcherType: matcherType, captureOnFailure: true, skip: skip, fullStacks: fullStacks}
 }
 
 func (this *matcherAdapter) Expect(actual interface{}, matcher Matcher, expected ...interface{}) {
 	match, pos, _, err := matcher.Match(actual, expected...)
 	if err != nil {
-		this.addError(err, actual)
+		this.addError(err, actual, matcher)
 	} else if !match {
-		this.addFailure(pos)
+		this.addFailure(pos, matcher)
 	}
 }
 
-func (this *matcherAdapter) addFailure(message Message) {
-	this.writeToLog(this.matcherType, message.Expectation(), message.Actual())
+func (this *matcherAdapter) addFailure(message Message, matcher Matcher) {
+	this.writeToLog(this.matcherType, message.Expectation(), message.Actual(), matcher)
 }
 
-func (this *matcherAdapter) addError(err error, actual interface{}) {
-	this.writeToLog(OtherError, err.Error(), actual)
+func (this *matcherAdapter) addError(err error, actual interface{}, matcher Matcher) {
+	this.writeToLog(OtherError, err.Error(), actual, matcher)
 }
 
-func (this *matcherAdapter) writeToLog(errortype ErrorType, message string, actual interface{}) {
-	stacktrace := toStackTrace(this.location)
-	e := newError(errortype, message, fmt.Sprint(actual), stacktrace)
+func (this *matcherAdapter) writeToLog(errortype ErrorType, message string, actual interface{}, matcher Matcher) {
+	e := newError(errortype, message, fmt.Sprint(withFormatter(actual)), this.stackTrace())
+	e.MatcherName = matcherName(matcher)
+	e.ActualValue = actual
 	this.log.AddError(e)
 }
 
-func toStackTrace(loc *Location) []*Location {
-	if loc != nil {
-		return []*Location{loc}
+// The frames of writeToLog, addFailure or addError, Expect and the two
+// methods of the Context, which are skipped when capturing the stack trace.
+const expectationFrames = 5
+
+// Must be called directly from writeToLog.
+func (this *matcherAdapter) stackTrace() []*Location {
+	if !this.captureOnFailure {
+		return this.stacktrace
 	}
-	return []*Location{}
+	stacktrace := skipFrames(callerStackTrace(), expectationFrames+this.skip)
+	if this.fullStacks {
+		return stacktrace
+	}
+	return trimStackTrace(stacktrace)
 }
 
 // Matchers are used in expectations to compare the actual and expected values.
@@ -76,6 +108,8 @@ type Message interface {
 	Expectation() string
 }
 
+// Creates the message of a custom matcher. The expectation is formatted
+// lazily, the same way as with Errorf.
 func Messagef(actual interface{}, expectationFormat string, expectationArgs ...interface{}) Message {
 	expectation := Errorf(expectationFormat, expectationArgs...)
 	return &message{actual, expectation}
@@ -97,10 +131,11 @@ func (this *message) Expectation() string {
 // Constructs an error message the same way as fmt.Sprintf(), but the string is
 // created lazily when it is used, if it is used at all. This avoids unnecessary
 // string parsing in matchers, because most of the time there are no failures
-// and thus the error messages are not used.
+// and thus the error messages are not used. The values are formatted with the
+// formatters registered with RegisterFormatter.
 func Errorf(format string, args ...interface{}) error {
 	return lazyError(func() string {
-		return fmt.Sprintf(format, args...)
+		return fmt.Sprintf(format, withFormatters(format, args)...)
 	})
 }
 
@@ -110,6 +145,49 @@ func (this lazyError) Error() string {
 	return this()
 }
 
+// The built-in matchers create their messages for every expectation, also
+// when it passes. For them there are variants of Errorf which store at most
+// three arguments directly, to avoid allocating the variadic arguments and the
+// closure of Errorf. The messages with more arguments are created only
+// when the expectation fails, so they use Errorf.
+func newMessage(actual interface{}, expectation error) Message {
+	return &message{actual, expectation}
+}
+
+// The format must not contain any verbs, so it is not parsed at all.
+func errorf0(format string) error {
+	return staticError(format)
+}
+
+func errorf1(format string, a interface{}) error {
+	return &fixedArgsError{format, 1, a, nil, nil}
+}
+
+func errorf2(format string, a interface{}, b interface{}) error {
+	return &fixedArgsError{format, 2, a, b, nil}
+}
+
+func errorf3(format string, a interface{}, b interface{}, c interface{}) error {
+	return &fixedArgsError{format, 3, a, b, c}
+}
+
+type staticError string
+
+func (this staticError) Error() string {
+	return string(this)
+}
+
+type fixedArgsError struct {
+	format  string
+	argc    int
+	a, b, c interface{}
+}
+
+func (this *fixedArgsError) Error() string {
+	args := []interface{}{this.a, this.b, this.c}[:this.argc]
+	return fmt.Sprintf(this.format, withFormatters(this.format, args)...)
+}
+
 // Easy array creation, to give multiple expected values to a matcher.
 func Values(values ...interface{}) []interface{} {
 	return values
@@ -126,15 +204,357 @@ func Not(matcher Matcher) Matcher {
 	}
 }
 
+// Combines two matchers with a logical AND. Matches when both matchers match.
+// The second matcher is not called if the first one does not match.
+func And(m1 Matcher, m2 Matcher) Matcher {
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		match1, pos1, neg1, err := m1(actual, expected)
+		if err != nil || !match1 {
+			return match1, pos1, neg1, err
+		}
+		match, pos, neg, err = m2(actual, expected)
+		if err != nil || !match {
+			return
+		}
+		neg = newMessage(neg1.Actual(), errorf2("%v, or %v", expectationOf{neg1}, expectationOf{neg}))
+		return
+	}
+}
+
+// Combines two matchers with a logical OR. Matches when either matcher matches.
+// The second matcher is not called if the first one matches.
+func Or(m1 Matcher, m2 Matcher) Matcher {
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		match1, pos1, neg1, err := m1(actual, expected)
+		if err != nil || match1 {
+			return match1, pos1, neg1, err
+		}
+		match, pos, neg, err = m2(actual, expected)
+		if err != nil || match {
+			return
+		}
+		pos = newMessage(pos1.Actual(), errorf2("%v, or %v", expectationOf{pos1}, expectationOf{pos}))
+		return
+	}
+}
+
+// Matches when all of the matchers match. Unlike And, all the matchers are
+// called, so that the failure message will list every failed expectation.
+func AllOf(matchers ...Matcher) Matcher {
+	if len(matchers) == 1 {
+		return matchers[0]
+	}
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		failures := make([]Message, 0)
+		negations := make([]Message, 0)
+		for _, matcher := range matchers {
+			m, p, n, e := matcher(actual, expected)
+			if e != nil {
+				return m, p, n, e
+			}
+			if m {
+				negations = append(negations, n)
+			} else {
+				failures = append(failures, p)
+			}
+		}
+
+		match = len(failures) == 0
+		pos = newMessage(actual, errorf1("%v", joinedExpectations{failures, ", and "}))
+		neg = newMessage(actual, errorf1("%v", joinedExpectations{negations, ", or "}))
+		return
+	}
+}
+
+// Matches when at least one of the matchers matches. The matchers are called
+// in order until the first one matches. Never matches when there are no matchers.
+func AnyOf(matchers ...Matcher) Matcher {
+	if len(matchers) == 1 {
+		return matchers[0]
+	}
+	if len(matchers) == 0 {
+		return func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+			pos = newMessage(actual, errorf0("matches any of the matchers, but AnyOf was given no matchers"))
+			neg = newMessage(actual, errorf0("does NOT match any of the matchers"))
+			return
+		}
+	}
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		failures := make([]Message, 0)
+		negations := make([]Message, 0)
+		for _, matcher := range matchers {
+			m, p, n, e := matcher(actual, expected)
+			if e != nil || m {
+				return m, p, n, e
+			}
+			failures = append(failures, p)
+			negations = append(negations, n)
+		}
+
+		pos = newMessage(actual, errorf1("%v", joinedExpectations{failures, ", or "}))
+		neg = newMessage(actual, errorf1("%v", joinedExpectations{negations, ", and "}))
+		return
+	}
+}
+
+// Applies the transformation to the actual value before passing it to the
+// inner matcher. For example:
+//
+//	c.Expect(person, Transform(ageOf, IsGreaterThan), 18)
+//
+// Panics in the transformation are reported as errors.
+func Transform(fn func(interface{}) interface{}, inner Matcher) Matcher {
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		var transformed interface{}
+		if e := recoverOnPanic(func() { transformed = fn(actual) }); e != nil {
+			err = Errorf("transformation of “%v” failed: %v", actual, e)
+			return
+		}
+
+		match, pos, neg, err = inner(transformed, expected)
+		if err != nil {
+			return
+		}
+		pos = newMessage(pos.Actual(), errorf2("%v, after transforming “%v”", expectationOf{pos}, actual))
+		neg = newMessage(neg.Actual(), errorf2("%v, after transforming “%v”", expectationOf{neg}, actual))
+		return
+	}
+}
+
+// Replaces the failure messages of the inner matcher. The messages are
+// formatted the same way as with Errorf, with the actual value as the only
+// argument, so it is shown with %v, or with %[1]v when it is shown more than
+// once. A literal percent sign is written as %%. Errors of the inner matcher
+// are reported unchanged.
+func WithMessage(posFormat string, negFormat string, inner Matcher) Matcher {
+	posError := formatWithActual(posFormat)
+	negError := formatWithActual(negFormat)
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		match, pos, neg, err = inner(actual, expected)
+		if err != nil {
+			return
+		}
+		pos = newMessage(pos.Actual(), posError(pos.Actual()))
+		neg = newMessage(neg.Actual(), negError(neg.Actual()))
+		return
+	}
+}
+
+// A message which does not show the actual value is formatted only once,
+// without arguments, because fmt would complain about an extra argument.
+func formatWithActual(format string) func(actual interface{}) error {
+	if static, ok := strings.CutSuffix(fmt.Sprintf(format, nil), "%!(EXTRA <nil>)"); ok {
+		return func(actual interface{}) error {
+			return errorf0(static)
+		}
+	}
+	return func(actual interface{}) error {
+		return errorf1(format, actual)
+	}
+}
+
+// Prefixes the failure messages of the inner matcher with the name, which
+// helps to identify reused matchers. Errors of the inner matcher are
+// reported unchanged.
+func Named(name string, inner Matcher) Matcher {
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		match, pos, neg, err = inner(actual, expected)
+		if err != nil {
+			return
+		}
+		pos = newMessage(pos.Actual(), errorf2("[%v] %v", name, expectationOf{pos}))
+		neg = newMessage(neg.Actual(), errorf2("[%v] %v", name, expectationOf{neg}))
+		return
+	}
+}
+
+// Used as a lazy argument for the messages, when a message contains the
+// expectations of other messages.
+type expectationOf struct {
+	message Message
+}
+
+func (this expectationOf) String() string {
+	return this.message.Expectation()
+}
+
+type joinedExpectations struct {
+	messages  []Message
+	separator string
+}
+
+func (this joinedExpectations) String() string {
+	s := ""
+	for i, message := range this.messages {
+		if i > 0 {
+			s += this.separator
+		}
+		s += message.Expectation()
+	}
+	return s
+}
+
 // The actual value must equal the expected value. For primitives the equality
 // operator is used. All other objects must implement the Equality interface.
 func Equals(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
 	match = areEqual(actual, expected)
-	pos = Messagef(actual, "equals “%v”", expected)
-	neg = Messagef(actual, "does NOT equal “%v”", expected)
+	pos = newMessage(actual, errorf2("equals “%v”%v", expected, appendedDiff{expected, actual}))
+	neg = newMessage(actual, errorf1("does NOT equal “%v”", expected))
+	return
+}
+
+// The actual value must be structurally equal to the expected value, as
+// determined by reflect.DeepEqual. Unlike Equals, this works also for slices,
+// maps and structs which contain them, and pointers are compared by the
+// values they point to.
+func DeepEquals(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	match = reflect.DeepEqual(actual, expected)
+	pos = newMessage(goSyntax{actual}, errorf1("deep equals “%#v”", expected))
+	neg = newMessage(goSyntax{actual}, errorf1("does NOT deep equal “%#v”", expected))
+	return
+}
+
+// Same as DeepEquals, but the failure message contains also a line-by-line
+// diff of the expected and actual values, which makes it easier to spot the
+// differences in large structs.
+func DeepEqualsWithDiff(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	match = reflect.DeepEqual(actual, expected)
+	pos = newMessage(goSyntax{actual}, errorf2("deep equals “%#v”\n%v", expected, valueDiff{expected, actual}))
+	neg = newMessage(goSyntax{actual}, errorf1("does NOT deep equal “%#v”", expected))
 	return
 }
 
+// Same as DeepEquals, but the named struct fields are ignored, by setting them
+// to their zero values in copies of the actual and expected values before
+// comparing them. Fields of nested structs are named with dot-separated paths,
+// for example "Meta.UpdatedAt". Gives an error if a field does not exist.
+func DeepEqualsIgnoring(fields ...string) Matcher {
+	return func(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		a, err := withoutFields(actual, fields)
+		if err != nil {
+			return
+		}
+		e, err := withoutFields(expected, fields)
+		if err != nil {
+			return
+		}
+
+		match = reflect.DeepEqual(a, e)
+		pos = newMessage(goSyntax{actual}, errorf2("deep equals “%#v”, ignoring “%v”", expected, fields))
+		neg = newMessage(goSyntax{actual}, errorf2("does NOT deep equal “%#v”, ignoring “%v”", expected, fields))
+		return
+	}
+}
+
+// Returns a copy of the value where the fields are set to their zero values.
+// The value itself is not modified, because the structs which are pointed to
+// along the paths are also copied.
+func withoutFields(value interface{}, paths []string) (result interface{}, err error) {
+	if len(paths) == 0 {
+		return value, nil
+	}
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil, Errorf("type error: expected a struct, but was “%v” of type “%T”", value, value)
+	}
+	if t := v.Type(); t.Kind() != reflect.Struct && (t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct) {
+		return nil, Errorf("type error: expected a struct, but was “%v” of type “%T”", value, value)
+	}
+	for _, path := range paths {
+		if err = checkFieldPath(v.Type(), strings.Split(path, "."), value, path); err != nil {
+			return
+		}
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	for _, path := range paths {
+		if err = zeroField(c, strings.Split(path, "."), value, path); err != nil {
+			return
+		}
+	}
+	return c.Interface(), nil
+}
+
+// The path is checked against the types of the fields, so that a misspelled
+// path gives an error also when there is a nil pointer along the path.
+func checkFieldPath(t reflect.Type, names []string, value interface{}, path string) error {
+	for _, name := range names {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return Errorf("illegal argument: “%T” has no field “%v”", value, path)
+		}
+		f, found := t.FieldByName(name)
+		if !found {
+			return Errorf("illegal argument: “%T” has no field “%v”", value, path)
+		}
+		if f.PkgPath != "" {
+			return Errorf("illegal argument: the field “%v” of “%T” is not exported", path, value)
+		}
+		t = f.Type
+	}
+	return nil
+}
+
+func zeroField(v reflect.Value, names []string, value interface{}, path string) error {
+	v, ok := copyPointee(v)
+	if !ok {
+		return nil
+	}
+	if v.Kind() != reflect.Struct {
+		return Errorf("illegal argument: “%T” has no field “%v”", value, path)
+	}
+	f, found := v.Type().FieldByName(names[0])
+	if !found {
+		return Errorf("illegal argument: “%T” has no field “%v”", value, path)
+	}
+	for i, index := range f.Index {
+		if i > 0 {
+			if !v.CanSet() {
+				break
+			}
+			if v, ok = copyPointee(v); !ok {
+				return nil
+			}
+		}
+		v = v.Field(index)
+	}
+	if !v.CanSet() {
+		return Errorf("illegal argument: the field “%v” of “%T” is not exported", path, value)
+	}
+	if len(names) == 1 {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	return zeroField(v, names[1:], value, path)
+}
+
+// If the value is a pointer, replaces it with a pointer to a copy of the
+// pointee and returns the copy. Returns false if the pointer is nil.
+func copyPointee(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() != reflect.Ptr {
+		return v, true
+	}
+	if v.IsNil() {
+		return v, false
+	}
+	c := reflect.New(v.Type().Elem())
+	c.Elem().Set(v.Elem())
+	v.Set(c)
+	return c.Elem(), true
+}
+
+// Prints the value in Go syntax, so that for example the types and
+// field names of structs are visible.
+type goSyntax struct {
+	value interface{}
+}
+
+func (this goSyntax) String() string {
+	return fmt.Sprintf("%#v", this.value)
+}
+
 func areEqual(a interface{}, b interface{}) bool {
 	if a2, ok := a.(Equality); ok {
 		return a2.Equals(b)
@@ -146,6 +566,17 @@ type Equality interface {
 	Equals(other interface{}) bool
 }
 
+// The actual value must equal one of the given values. The values are compared
+// the same way as in Equals. Never matches when no values are given.
+func IsOneOf(values ...interface{}) Matcher {
+	return func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		match = arrayContains(values, actual)
+		pos = newMessage(actual, errorf1("is one of “%v”", values))
+		neg = newMessage(actual, errorf1("is NOT one of “%v”", values))
+		return
+	}
+}
+
 // The actual value must be a pointer to the same object as the expected value.
 func IsSame(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
 	ptr1, err := pointerOf(actual)
@@ -157,8 +588,8 @@ func IsSame(actual interface{}, expected interface{}) (match bool, pos Message,
 		return
 	}
 	match = ptr1 == ptr2
-	pos = Messagef(actual, "is same as “%v”", expected)
-	neg = Messagef(actual, "is NOT same as “%v”", expected)
+	pos = newMessage(actual, errorf1("is same as “%v”", expected))
+	neg = newMessage(actual, errorf1("is NOT same as “%v”", expected))
 	return
 }
 
@@ -177,8 +608,8 @@ func pointerOf(value interface{}) (ptr uintptr, err error) {
 // for discussion on how in Go typed nil values can turn into non-nil interface values.
 func IsNil(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
 	match = actual == nil || isNilPointerInsideInterfaceValue(actual)
-	pos = Messagef(actual, "is <nil>")
-	neg = Messagef(actual, "is NOT <nil>")
+	pos = newMessage(actual, errorf0("is <nil>"))
+	neg = newMessage(actual, errorf0("is NOT <nil>"))
 	return
 }
 
@@ -193,27 +624,39 @@ func isNilPointerInsideInterfaceValue(value interface{}) bool {
 // The actual value must be <true>.
 func IsTrue(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
 	match = actual.(bool) == true
-	pos = Messagef(actual, "is <true>")
-	neg = Messagef(actual, "is NOT <true>")
+	pos = newMessage(actual, errorf0("is <true>"))
+	neg = newMessage(actual, errorf0("is NOT <true>"))
 	return
 }
 
 // The actual value must be <false>.
 func IsFalse(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
 	match = actual.(bool) == false
-	pos = Messagef(actual, "is <false>")
-	neg = Messagef(actual, "is NOT <false>")
+	pos = newMessage(actual, errorf0("is <false>"))
+	neg = newMessage(actual, errorf0("is NOT <false>"))
 	return
 }
 
 // The actual value must satisfy the given criteria.
 func Satisfies(actual interface{}, criteria interface{}) (match bool, pos Message, neg Message, err error) {
 	match = criteria.(bool) == true
-	pos = Messagef(actual, "satisfies the criteria")
-	neg = Messagef(actual, "does NOT satisfy the criteria")
+	pos = newMessage(actual, errorf0("satisfies the criteria"))
+	neg = newMessage(actual, errorf0("does NOT satisfy the criteria"))
 	return
 }
 
+// The actual value must pass the predicate. Unlike with Satisfies, the
+// predicate is called by the matcher, so that it is evaluated only once
+// for each expectation.
+func Passes(predicate func(actual interface{}) bool) Matcher {
+	return func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		match = predicate(actual)
+		pos = newMessage(actual, errorf0("passes the predicate"))
+		neg = newMessage(actual, errorf0("does NOT pass the predicate"))
+		return
+	}
+}
+
 // The actual value must be within delta from the expected value.
 func IsWithin(delta float64) Matcher {
 	return func(actual_ interface{}, expected_ interface{}) (match bool, pos Message, neg Message, err error) {
@@ -227,89 +670,435 @@ func IsWithin(delta float64) Matcher {
 		}
 
 		match = math.Abs(expected-actual) < delta
-		pos = Messagef(actual, "is within %v ± %v", expected, delta)
-		neg = Messagef(actual, "is NOT within %v ± %v", expected, delta)
+		pos = newMessage(actual, errorf2("is within %v ± %v", expected, delta))
+		neg = newMessage(actual, errorf2("is NOT within %v ± %v", expected, delta))
+		return
+	}
+}
+
+// The actual value must be within a fraction of the expected value, for
+// example 0.001 means ± 0.1%. When the expected value is zero, the fraction
+// is used as an absolute delta.
+func IsWithinRelative(fraction float64) Matcher {
+	return func(actual_ interface{}, expected_ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toFloat64(actual_)
+		if err != nil {
+			return
+		}
+		expected, err := toFloat64(expected_)
+		if err != nil {
+			return
+		}
+
+		if expected == 0 {
+			match = math.Abs(actual) < fraction
+		} else {
+			match = math.Abs(expected-actual)/math.Abs(expected) < fraction
+		}
+		pos = newMessage(actual, errorf2("is within %v ± %v%%", expected, fraction*100))
+		neg = newMessage(actual, errorf2("is NOT within %v ± %v%%", expected, fraction*100))
 		return
 	}
 }
 
 func toFloat64(actual interface{}) (result float64, err error) {
 	switch v := actual.(type) {
+	case int:
+		result = float64(v)
+	case int8:
+		result = float64(v)
+	case int16:
+		result = float64(v)
+	case int32:
+		result = float64(v)
+	case int64:
+		result = float64(v)
+	case uint:
+		result = float64(v)
+	case uint8:
+		result = float64(v)
+	case uint16:
+		result = float64(v)
+	case uint32:
+		result = float64(v)
+	case uint64:
+		result = float64(v)
 	case float32:
 		result = float64(v)
 	case float64:
 		result = float64(v)
 	default:
-		err = Errorf("type error: expected a float, but was “%v” of type “%T”", actual, actual)
+		err = Errorf("type error: expected a number, but was “%v” of type “%T”", actual, actual)
 	}
 	return
 }
 
-// The actual collection must contain the expected value.
-func Contains(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
-	actual, err := toArray(actual_)
+// The actual value must be greater than the expected value.
+func IsGreaterThan(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	a, b, err := toFloat64Pair(actual, expected)
 	if err != nil {
 		return
 	}
 
-	match = arrayContains(actual, expected)
-	pos = Messagef(actual, "contains “%v”", expected)
-	neg = Messagef(actual, "does NOT contain “%v”", expected)
+	match = a > b
+	pos = newMessage(actual, errorf1("is greater than “%v”", expected))
+	neg = newMessage(actual, errorf1("is NOT greater than “%v”", expected))
 	return
 }
 
-func toArray(values interface{}) ([]interface{}, error) {
-	result := make([]interface{}, 0)
-
-	// list to array
-	if list, ok := values.(*list.List); ok {
-		for e := list.Front(); e != nil; e = e.Next() {
-			result = append(result, e.Value)
-		}
-		return result, nil
+// The actual value must be greater than or equal to the expected value.
+func IsGreaterThanOrEqualTo(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	a, b, err := toFloat64Pair(actual, expected)
+	if err != nil {
+		return
 	}
 
-	switch v := reflect.ValueOf(values); v.Kind() {
+	match = a >= b
+	pos = newMessage(actual, errorf1("is greater than or equal to “%v”", expected))
+	neg = newMessage(actual, errorf1("is NOT greater than or equal to “%v”", expected))
+	return
+}
 
-	// array to array (copy)
-	case reflect.Array, reflect.Slice:
-		arr := v
-		for i := 0; i < arr.Len(); i++ {
-			obj := arr.Index(i).Interface()
-			result = append(result, obj)
-		}
+// The actual value must be less than the expected value.
+func IsLessThan(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	a, b, err := toFloat64Pair(actual, expected)
+	if err != nil {
+		return
+	}
 
-	// channel to array
-	case reflect.Chan:
-		ch := v
-		for {
-			if x, ok := ch.Recv(); ok {
-				obj := x.Interface()
-				result = append(result, obj)
-			} else {
-				break
-			}
-		}
+	match = a < b
+	pos = newMessage(actual, errorf1("is less than “%v”", expected))
+	neg = newMessage(actual, errorf1("is NOT less than “%v”", expected))
+	return
+}
 
-	// unknown type
-	default:
-		return nil, Errorf("type error: expected a collection type, but was “%v” of type “%T”", values, values)
+// The actual value must be less than or equal to the expected value.
+func IsLessThanOrEqualTo(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	a, b, err := toFloat64Pair(actual, expected)
+	if err != nil {
+		return
 	}
-	return result, nil
-}
 
-func arrayContains(haystack []interface{}, needle interface{}) bool {
-	_, found := findIndex(haystack, needle)
-	return found
+	match = a <= b
+	pos = newMessage(actual, errorf1("is less than or equal to “%v”", expected))
+	neg = newMessage(actual, errorf1("is NOT less than or equal to “%v”", expected))
+	return
 }
 
-func findIndex(haystack []interface{}, needle interface{}) (idx int, found bool) {
-	for i := 0; i < len(haystack); i++ {
-		if areEqual(haystack[i], needle) {
-			return i, true
+// The actual value must be within the closed range [low, high].
+func IsBetween(low float64, high float64) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toFloat64(actual_)
+		if err != nil {
+			return
 		}
+
+		match = low <= actual && actual <= high
+		pos = newMessage(actual_, errorf2("is between %v and %v", low, high))
+		neg = newMessage(actual_, errorf2("is NOT between %v and %v", low, high))
+		return
 	}
-	return -1, false
+}
+
+// The actual value must be within the open range (low, high).
+func IsBetweenExclusive(low float64, high float64) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toFloat64(actual_)
+		if err != nil {
+			return
+		}
+
+		match = low < actual && actual < high
+		pos = newMessage(actual_, errorf2("is between %v and %v, exclusive", low, high))
+		neg = newMessage(actual_, errorf2("is NOT between %v and %v, exclusive", low, high))
+		return
+	}
+}
+
+// The actual value must be greater than zero.
+func IsPositive(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toFloat64(actual_)
+	if err != nil {
+		return
+	}
+
+	match = actual > 0
+	pos = newMessage(actual_, errorf0("is positive"))
+	neg = newMessage(actual_, errorf0("is NOT positive"))
+	return
+}
+
+// The actual value must be less than zero.
+func IsNegative(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toFloat64(actual_)
+	if err != nil {
+		return
+	}
+
+	match = actual < 0
+	pos = newMessage(actual_, errorf0("is negative"))
+	neg = newMessage(actual_, errorf0("is NOT negative"))
+	return
+}
+
+// The actual value must be greater than or equal to zero.
+func IsNonNegative(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toFloat64(actual_)
+	if err != nil {
+		return
+	}
+
+	match = actual >= 0
+	pos = newMessage(actual_, errorf0("is non-negative"))
+	neg = newMessage(actual_, errorf0("is NOT non-negative"))
+	return
+}
+
+// The actual value must be the zero value of its type. Numbers of any type
+// are compared to zero and other comparable values to their type's zero value.
+func IsZero(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	if number, notNumber := toFloat64(actual); notNumber == nil {
+		match = number == 0
+	} else if actual == nil {
+		match = true
+	} else if t := reflect.TypeOf(actual); t.Comparable() {
+		match = areEqual(actual, reflect.Zero(t).Interface())
+	} else {
+		err = Errorf("type error: expected a comparable value, but was “%v” of type “%T”", actual, actual)
+		return
+	}
+
+	pos = newMessage(actual, errorf0("is the zero value"))
+	neg = newMessage(actual, errorf0("is NOT the zero value"))
+	return
+}
+
+// The actual value must be NaN (not-a-number).
+func IsNaN(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toFloat64(actual_)
+	if err != nil {
+		return
+	}
+
+	match = math.IsNaN(actual)
+	pos = newMessage(actual_, errorf0("is NaN"))
+	neg = newMessage(actual_, errorf0("is NOT NaN"))
+	return
+}
+
+// The actual value must be infinite. The optional expected value is the sign
+// of the infinity: +1 for positive, -1 for negative and 0 for either.
+func IsInfinite(actual_ interface{}, sign_ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toFloat64(actual_)
+	if err != nil {
+		return
+	}
+	sign := 0.0
+	if sign_ != nil {
+		sign, err = toFloat64(sign_)
+		if err != nil {
+			return
+		}
+	}
+
+	infinity := "±Inf"
+	if sign > 0 {
+		infinity = "+Inf"
+	} else if sign < 0 {
+		infinity = "-Inf"
+	}
+
+	match = math.IsInf(actual, int(sign))
+	pos = newMessage(actual_, errorf1("is %v", infinity))
+	neg = newMessage(actual_, errorf1("is NOT %v", infinity))
+	return
+}
+
+// The actual value must be an even integer.
+func IsEven(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	even, err := isEven(actual)
+	if err != nil {
+		return
+	}
+
+	match = even
+	pos = newMessage(actual, errorf0("is even"))
+	neg = newMessage(actual, errorf0("is NOT even"))
+	return
+}
+
+// The actual value must be an odd integer.
+func IsOdd(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	even, err := isEven(actual)
+	if err != nil {
+		return
+	}
+
+	match = !even
+	pos = newMessage(actual, errorf0("is odd"))
+	neg = newMessage(actual, errorf0("is NOT odd"))
+	return
+}
+
+func isEven(value interface{}) (even bool, err error) {
+	if abs, ok := absOfInteger(value); ok {
+		return abs%2 == 0, nil
+	}
+	number, err := toFloat64(value)
+	if err != nil {
+		return
+	}
+	if math.IsInf(number, 0) || math.Trunc(number) != number {
+		err = Errorf("type error: expected an integer, but was “%v” of type “%T”", value, value)
+		return
+	}
+	even = math.Mod(number, 2) == 0
+	return
+}
+
+// The actual value must be a multiple of the divisor.
+func IsMultipleOf(divisor_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, divisor, err := toFloat64Pair(actual_, divisor_)
+		if err != nil {
+			return
+		}
+		if divisor == 0 {
+			err = Errorf("illegal argument: IsMultipleOf requires a non-zero divisor")
+			return
+		}
+
+		a, aIsInteger := absOfInteger(actual_)
+		d, dIsInteger := absOfInteger(divisor_)
+		if aIsInteger && dIsInteger {
+			match = a%d == 0
+		} else if isInt64(actual) && isInt64(divisor) {
+			match = int64(actual)%int64(divisor) == 0
+		} else {
+			match = math.Mod(actual, divisor) == 0
+		}
+		pos = newMessage(actual_, errorf1("is a multiple of “%v”", divisor_))
+		neg = newMessage(actual_, errorf1("is NOT a multiple of “%v”", divisor_))
+		return
+	}
+}
+
+// The absolute value of an integer, calculated without converting it to
+// float64, which would lose precision above 2^53. Divisibility does not
+// depend on the signs, and the absolute value of every integer fits into
+// an uint64. Returns false if the value is not of an integer type.
+func absOfInteger(value interface{}) (abs uint64, ok bool) {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if i < 0 {
+			return -uint64(i), true
+		}
+		return uint64(i), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true
+	}
+	return 0, false
+}
+
+func isInt64(value float64) bool {
+	return math.Trunc(value) == value && math.Abs(value) < math.MaxInt64
+}
+
+func toFloat64Pair(actual interface{}, expected interface{}) (a float64, b float64, err error) {
+	a, err = toFloat64(actual)
+	if err != nil {
+		return
+	}
+	b, err = toFloat64(expected)
+	return
+}
+
+// The actual collection must contain the expected value.
+func Contains(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toArray(actual_)
+	if err != nil {
+		return
+	}
+
+	match = arrayContains(actual, expected)
+	pos = newMessage(actual, errorf1("contains “%v”", expected))
+	neg = newMessage(actual, errorf1("does NOT contain “%v”", expected))
+	return
+}
+
+func toArray(values interface{}) ([]interface{}, error) {
+	result := make([]interface{}, 0)
+
+	// list to array
+	if list, ok := values.(*list.List); ok {
+		result = make([]interface{}, 0, list.Len())
+		for e := list.Front(); e != nil; e = e.Next() {
+			result = append(result, e.Value)
+		}
+		return result, nil
+	}
+
+	// array to array (copy), without reflection for the most common type
+	if arr, ok := values.([]interface{}); ok {
+		result = make([]interface{}, len(arr))
+		copy(result, arr)
+		return result, nil
+	}
+
+	switch v := reflect.ValueOf(values); v.Kind() {
+
+	// array to array (copy)
+	case reflect.Array, reflect.Slice:
+		arr := v
+		result = make([]interface{}, arr.Len())
+		for i := range result {
+			result[i] = arr.Index(i).Interface()
+		}
+
+	// channel to array
+	case reflect.Chan:
+		ch := v
+		for {
+			if x, ok := ch.Recv(); ok {
+				obj := x.Interface()
+				result = append(result, obj)
+			} else {
+				break
+			}
+		}
+
+	// map values to array, sorted by their keys for a repeatable order
+	case reflect.Map:
+		m := v
+		keys := m.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			obj := m.MapIndex(key).Interface()
+			result = append(result, obj)
+		}
+
+	// unknown type
+	default:
+		return nil, Errorf("type error: expected a collection type, but was “%v” of type “%T”", values, values)
+	}
+	return result, nil
+}
+
+func arrayContains(haystack []interface{}, needle interface{}) bool {
+	_, found := findIndex(haystack, needle)
+	return found
+}
+
+func findIndex(haystack []interface{}, needle interface{}) (idx int, found bool) {
+	for i := 0; i < len(haystack); i++ {
+		if areEqual(haystack[i], needle) {
+			return i, true
+		}
+	}
+	return -1, false
 }
 
 // The actual collection must contain all expected elements,
@@ -334,8 +1123,8 @@ func ContainsAll(actual_ interface{}, expected_ interface{}) (match bool, pos Me
 	}
 
 	match = containsAll
-	pos = Messagef(actual, "contains all of “%v”", expected)
-	neg = Messagef(actual, "does NOT contain all of “%v”", expected)
+	pos = newMessage(actual, errorf1("contains all of “%v”", expected))
+	neg = newMessage(actual, errorf1("does NOT contain all of “%v”", expected))
 	return
 }
 
@@ -359,11 +1148,39 @@ func ContainsAny(actual_ interface{}, expected_ interface{}) (match bool, pos Me
 	}
 
 	match = containsAny
-	pos = Messagef(actual, "contains any of “%v”", expected)
-	neg = Messagef(actual, "does NOT contain any of “%v”", expected)
+	pos = newMessage(actual, errorf1("contains any of “%v”", expected))
+	neg = newMessage(actual, errorf1("does NOT contain any of “%v”", expected))
 	return
 }
 
+// Every element of the actual collection must be one of the allowed elements,
+// but not all of the allowed elements need to be present. Useful for example
+// for checking that an enum-like field takes only valid values.
+func ContainsOnly(allowed_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		allowed, err := toArray(allowed_)
+		if err != nil {
+			return
+		}
+
+		match = true
+		pos = newMessage(actual, errorf1("contains only “%v”", allowed))
+		for _, element := range actual {
+			if !arrayContains(allowed, element) {
+				match = false
+				pos = newMessage(actual, errorf2("contains only “%v”, but “%v” was unexpected", allowed, element))
+				break
+			}
+		}
+		neg = newMessage(actual, errorf1("does NOT contain only “%v”", allowed))
+		return
+	}
+}
+
 // The actual collection must contain all expected elements and nothing else.
 // The order of elements is not significant.
 func ContainsExactly(actual_ interface{}, expected_ interface{}) (match bool, pos Message, neg Message, err error) {
@@ -376,22 +1193,53 @@ func ContainsExactly(actual_ interface{}, expected_ interface{}) (match bool, po
 		return
 	}
 
-	containsAll := true
-	remaining := make([]interface{}, 0)
-	remaining = append(remaining, actual...)
-	for i := 0; i < len(expected); i++ {
-		if idx, found := findIndex(remaining, expected[i]); found {
-			remaining = append(remaining[:idx], remaining[idx+1:]...)
+	missing, unexpected := differences(actual, expected)
+
+	match = len(missing) == 0 && len(unexpected) == 0
+	switch {
+	case len(missing) > 0 && len(unexpected) > 0:
+		pos = newMessage(actual, errorf3("contains exactly “%v”, but “%v” were missing and “%v” were unexpected", expected, missing, unexpected))
+	case len(missing) > 0:
+		pos = newMessage(actual, errorf2("contains exactly “%v”, but “%v” were missing", expected, missing))
+	case len(unexpected) > 0:
+		pos = newMessage(actual, errorf2("contains exactly “%v”, but “%v” were unexpected", expected, unexpected))
+	default:
+		pos = newMessage(actual, errorf1("contains exactly “%v”", expected))
+	}
+	neg = newMessage(actual, errorf1("does NOT contain exactly “%v”", expected))
+	return
+}
+
+// Returns the expected elements which are not in the actual collection and
+// the actual elements which are not expected. Duplicates are counted, so that
+// an element which is expected twice must be in the actual collection twice.
+func differences(actual []interface{}, expected []interface{}) (missing []interface{}, unexpected []interface{}) {
+	missing = make([]interface{}, 0)
+	unexpected = append(make([]interface{}, 0, len(actual)), actual...)
+	for _, element := range expected {
+		if idx, found := findIndex(unexpected, element); found {
+			unexpected = append(unexpected[:idx], unexpected[idx+1:]...)
 		} else {
-			containsAll = false
-			break
+			missing = append(missing, element)
 		}
 	}
+	return missing, unexpected
+}
 
-	match = containsAll && len(remaining) == 0
-	pos = Messagef(actual, "contains exactly “%v”", expected)
-	neg = Messagef(actual, "does NOT contain exactly “%v”", expected)
-	return
+// Removes one occurrence of each of the elements from a copy of the
+// collection. Returns the remaining elements and the index of the first
+// element which was not found, or -1 if all were found.
+func removeEach(collection []interface{}, elements []interface{}) (remaining []interface{}, missing int) {
+	remaining = make([]interface{}, 0)
+	remaining = append(remaining, collection...)
+	for i := 0; i < len(elements); i++ {
+		if idx, found := findIndex(remaining, elements[i]); found {
+			remaining = append(remaining[:idx], remaining[idx+1:]...)
+		} else {
+			return remaining, i
+		}
+	}
+	return remaining, -1
 }
 
 // The actual collection must contain all expected elements, in the same order, and nothing else.
@@ -405,16 +1253,20 @@ func ContainsInOrder(actual_ interface{}, expected_ interface{}) (match bool, po
 		return
 	}
 
-	containsInOrder := len(actual) == len(expected)
+	match = true
+	pos = newMessage(actual, errorf1("contains in order “%v”", expected))
 	for i := 0; i < len(actual) && i < len(expected); i++ {
 		if !areEqual(actual[i], expected[i]) {
-			containsInOrder = false
+			match = false
+			pos = newMessage(actual, Errorf("contains in order “%v”, but at index %v expected “%v” but was “%v”", expected, i, expected[i], actual[i]))
+			break
 		}
 	}
-
-	match = containsInOrder
-	pos = Messagef(actual, "contains in order “%v”", expected)
-	neg = Messagef(actual, "does NOT contain in order “%v”", expected)
+	if match && len(actual) != len(expected) {
+		match = false
+		pos = newMessage(actual, errorf3("contains in order “%v”, but had %v elements instead of %v", expected, len(actual), len(expected)))
+	}
+	neg = newMessage(actual, errorf1("does NOT contain in order “%v”", expected))
 	return
 }
 
@@ -432,22 +1284,1111 @@ func ContainsInPartialOrder(actual_ interface{}, expected_ interface{}) (match b
 		return
 	}
 
-	containsInPartialOrder := true
-	for ie, ia := 0, 0; ie < len(expected); {
+	match = true
+	pos = newMessage(actual, errorf1("contains in partial order “%v”", expected))
+	previous := -1 // index of the actual element which matched the previous expected element
+	for ie, ia := 0, 0; ie < len(expected); ia++ {
 		if ia >= len(actual) {
-			containsInPartialOrder = false
+			match = false
+			if previous < 0 {
+				pos = newMessage(actual, errorf2("contains in partial order “%v”, but “%v” was not found", expected, expected[ie]))
+			} else {
+				pos = newMessage(actual, errorf3("contains in partial order “%v”, but “%v” was not found after index %v", expected, expected[ie], previous))
+			}
 			break
 		}
 		if areEqual(actual[ia], expected[ie]) {
+			previous = ia
 			ie++
-			ia++
-		} else {
-			ia++
 		}
 	}
-
-	match = containsInPartialOrder
-	pos = Messagef(actual, "contains in partial order “%v”", expected)
-	neg = Messagef(actual, "does NOT contain in partial order “%v”", expected)
+	neg = newMessage(actual, errorf1("does NOT contain in partial order “%v”", expected))
 	return
 }
+
+// The actual collection must contain the elements of the sequence next to
+// each other and in the same order. An empty sequence is contained in
+// every collection.
+func ContainsSequence(sequence_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		sequence, err := toArray(sequence_)
+		if err != nil {
+			return
+		}
+
+		match = indexOfSequence(actual, sequence) >= 0
+		pos = newMessage(actual, errorf1("contains sequence “%v”", sequence))
+		neg = newMessage(actual, errorf1("does NOT contain sequence “%v”", sequence))
+		return
+	}
+}
+
+// The first elements of the actual collection must equal the elements of
+// the prefix. An empty prefix matches every collection.
+func StartsWithSlice(prefix_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		prefix, err := toArray(prefix_)
+		if err != nil {
+			return
+		}
+
+		neg = newMessage(actual, errorf1("does NOT start with “%v”", prefix))
+		if len(actual) < len(prefix) {
+			pos = newMessage(actual, errorf2("starts with “%v”, but had fewer than %v elements", prefix, len(prefix)))
+			return
+		}
+		start := actual[:len(prefix)]
+		match = areEqualArrays(start, prefix)
+		pos = newMessage(actual, errorf2("starts with “%v”, but started with “%v”", prefix, start))
+		return
+	}
+}
+
+// The last elements of the actual collection must equal the elements of
+// the suffix. An empty suffix matches every collection.
+func EndsWithSlice(suffix_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		suffix, err := toArray(suffix_)
+		if err != nil {
+			return
+		}
+
+		neg = newMessage(actual, errorf1("does NOT end with “%v”", suffix))
+		if len(actual) < len(suffix) {
+			pos = newMessage(actual, errorf2("ends with “%v”, but had fewer than %v elements", suffix, len(suffix)))
+			return
+		}
+		end := actual[len(actual)-len(suffix):]
+		match = areEqualArrays(end, suffix)
+		pos = newMessage(actual, errorf2("ends with “%v”, but ended with “%v”", suffix, end))
+		return
+	}
+}
+
+func indexOfSequence(array []interface{}, sequence []interface{}) int {
+	for start := 0; start+len(sequence) <= len(array); start++ {
+		if areEqualArrays(array[start:start+len(sequence)], sequence) {
+			return start
+		}
+	}
+	return -1
+}
+
+func areEqualArrays(a []interface{}, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !areEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// The actual collection must be sorted in ascending order. The elements
+// must be numbers or strings.
+func IsSorted(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	return isSortedBy(actual_, naturalOrder)
+}
+
+// The actual collection must be sorted in ascending order,
+// as determined by the less function.
+func IsSortedBy(less func(a, b interface{}) bool) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		return isSortedBy(actual_, func(a, b interface{}) (bool, error) {
+			return less(a, b), nil
+		})
+	}
+}
+
+func isSortedBy(actual_ interface{}, less func(a, b interface{}) (bool, error)) (match bool, pos Message, neg Message, err error) {
+	actual, err := toArray(actual_)
+	if err != nil {
+		return
+	}
+
+	unsorted := -1
+	for i := 1; i < len(actual) && unsorted < 0; i++ {
+		isLess, err := less(actual[i], actual[i-1])
+		if err != nil {
+			return false, nil, nil, err
+		}
+		if isLess {
+			unsorted = i
+		}
+	}
+
+	match = unsorted < 0
+	if match {
+		pos = newMessage(actual, errorf0("is sorted"))
+	} else {
+		pos = newMessage(actual, Errorf("is sorted, but “%v” at index %v was less than “%v” at index %v",
+			actual[unsorted], unsorted, actual[unsorted-1], unsorted-1))
+	}
+	neg = newMessage(actual, errorf0("is NOT sorted"))
+	return
+}
+
+func naturalOrder(a interface{}, b interface{}) (bool, error) {
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return as < bs, nil
+		}
+	}
+	af, bf, err := toFloat64Pair(a, b)
+	if err != nil {
+		return false, Errorf("type error: expected numbers or strings, but was “%v” of type “%T” and “%v” of type “%T”", a, a, b, b)
+	}
+	return af < bf, nil
+}
+
+// Every number in the actual collection must be greater than or equal to the
+// number before it.
+func IsMonotonicallyIncreasing(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	return isMonotonic(actual, "monotonically increasing", "less than", func(prev, next float64) bool { return next >= prev })
+}
+
+// Every number in the actual collection must be less than or equal to the
+// number before it.
+func IsMonotonicallyDecreasing(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	return isMonotonic(actual, "monotonically decreasing", "greater than", func(prev, next float64) bool { return next <= prev })
+}
+
+// Every number in the actual collection must be greater than the number
+// before it.
+func IsStrictlyIncreasing(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	return isMonotonic(actual, "strictly increasing", "not greater than", func(prev, next float64) bool { return next > prev })
+}
+
+// Every number in the actual collection must be less than the number
+// before it.
+func IsStrictlyDecreasing(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	return isMonotonic(actual, "strictly decreasing", "not less than", func(prev, next float64) bool { return next < prev })
+}
+
+func isMonotonic(actual_ interface{}, order string, violation string, inOrder func(prev, next float64) bool) (match bool, pos Message, neg Message, err error) {
+	actual, err := toArray(actual_)
+	if err != nil {
+		return
+	}
+	numbers := make([]float64, len(actual))
+	for i, element := range actual {
+		if numbers[i], err = toFloat64(element); err != nil {
+			return
+		}
+	}
+
+	match = true
+	pos = newMessage(actual, errorf1("is %v", order))
+	for i := 1; i < len(numbers) && match; i++ {
+		if !inOrder(numbers[i-1], numbers[i]) {
+			match = false
+			pos = newMessage(actual, Errorf("is %v, but “%v” at index %v was %v “%v” at index %v",
+				order, actual[i], i, violation, actual[i-1], i-1))
+		}
+	}
+	neg = newMessage(actual, errorf1("is NOT %v", order))
+	return
+}
+
+// All elements of the actual collection must be contained in the superset.
+func IsSubsetOf(superset_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		superset, err := toArray(superset_)
+		if err != nil {
+			return
+		}
+
+		match = true
+		pos = newMessage(actual, errorf1("is a subset of “%v”", superset))
+		for _, element := range actual {
+			if !arrayContains(superset, element) {
+				match = false
+				pos = newMessage(actual, errorf2("is a subset of “%v”, but “%v” was not in it", superset, element))
+				break
+			}
+		}
+		neg = newMessage(actual, errorf1("is NOT a subset of “%v”", superset))
+		return
+	}
+}
+
+// The actual collection must contain all elements of the subset. When the
+// subset contains the same element many times, also the actual collection
+// must contain it at least as many times.
+func IsSupersetOf(subset_ interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		subset, err := toArray(subset_)
+		if err != nil {
+			return
+		}
+
+		_, missing := removeEach(actual, subset)
+
+		match = missing < 0
+		if match {
+			pos = newMessage(actual, errorf1("is a superset of “%v”", subset))
+		} else {
+			pos = newMessage(actual, errorf2("is a superset of “%v”, but “%v” was not in it", subset, subset[missing]))
+		}
+		neg = newMessage(actual, errorf1("is NOT a superset of “%v”", subset))
+		return
+	}
+}
+
+// The actual collection must have the same elements as the expected
+// collection, each the same number of times, but in any order.
+func IsPermutationOf(actual_ interface{}, expected_ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toArray(actual_)
+	if err != nil {
+		return
+	}
+	expected, err := toArray(expected_)
+	if err != nil {
+		return
+	}
+
+	remaining, missing := removeEach(actual, expected)
+
+	match = missing < 0 && len(remaining) == 0
+	pos = newMessage(actual, errorf1("is a permutation of “%v”", expected))
+	neg = newMessage(actual, errorf1("is NOT a permutation of “%v”", expected))
+	return
+}
+
+// The actual collection must not contain any element twice.
+func IsDistinct(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toArray(actual_)
+	if err != nil {
+		return
+	}
+
+	match = true
+	pos = newMessage(actual, errorf0("has distinct elements"))
+	for i := 1; i < len(actual) && match; i++ {
+		if arrayContains(actual[:i], actual[i]) {
+			match = false
+			pos = newMessage(actual, errorf1("has distinct elements, but “%v” occurred more than once", actual[i]))
+		}
+	}
+	neg = newMessage(actual, errorf0("does NOT have distinct elements"))
+	return
+}
+
+// Every element of the actual collection must match the inner matcher, which
+// is given the expected value unchanged. For example:
+//
+//	c.Expect(prices, AllElements(IsPositive))
+//	c.Expect(names, AllElements(HasPrefix("user_")))
+//
+// Matches always when the collection is empty.
+func AllElements(inner Matcher) Matcher {
+	return func(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		i, p, err := findElement(actual, expected, inner, false)
+		if err != nil {
+			return
+		}
+
+		match = i < 0
+		pos = newMessage(actual, errorf0("has all elements matching"))
+		if !match {
+			pos = newMessage(actual, errorf3("has all elements matching, but element at index %v (“%v”) failed: %v",
+				i, actual[i], expectationOf{p}))
+		}
+		neg = newMessage(actual, errorf0("does NOT have all elements matching"))
+		return
+	}
+}
+
+// At least one element of the actual collection must match the inner matcher,
+// which is given the expected value unchanged. Never matches when the
+// collection is empty.
+func AnyElement(inner Matcher) Matcher {
+	return func(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		i, _, err := findElement(actual, expected, inner, true)
+		if err != nil {
+			return
+		}
+
+		match = i >= 0
+		pos = newMessage(actual, errorf1("has an element matching, but none did (checked %v elements)", len(actual)))
+		neg = newMessage(actual, errorf0("does NOT have an element matching"))
+		if match {
+			neg = newMessage(actual, errorf2("does NOT have an element matching, but element at index %v (“%v”) matched",
+				i, actual[i]))
+		}
+		return
+	}
+}
+
+// No element of the actual collection may match the inner matcher, which
+// is given the expected value unchanged. Same as Not(AnyElement(inner)),
+// but reads better. Matches always when the collection is empty.
+func NoneElement(inner Matcher) Matcher {
+	return func(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		i, _, err := findElement(actual, expected, inner, true)
+		if err != nil {
+			return
+		}
+
+		match = i < 0
+		pos = newMessage(actual, errorf0("has no element matching"))
+		if !match {
+			pos = newMessage(actual, errorf2("has no element matching, but element at index %v (“%v”) matched",
+				i, actual[i]))
+		}
+		neg = newMessage(actual, errorf1("has an element matching, but none did (checked %v elements)", len(actual)))
+		return
+	}
+}
+
+// Returns the index of the first element for which the inner matcher's
+// result is the wanted result, or -1 if there is no such element. The pos
+// message is that of the returned element.
+func findElement(actual []interface{}, expected interface{}, inner Matcher, want bool) (index int, pos Message, err error) {
+	for i, element := range actual {
+		m, p, _, e := inner(element, expected)
+		if e != nil || m == want {
+			return i, p, e
+		}
+	}
+	return -1, nil, nil
+}
+
+// The actual collection must have the expected length. Works with arrays,
+// slices, strings, maps and channels.
+func HasLength(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	length, err := lengthOf(actual)
+	if err != nil {
+		return
+	}
+	match, err = isLength(length, expected)
+	if err != nil {
+		return
+	}
+
+	pos = newMessage(actual, errorf1("has length “%v”", expected))
+	neg = newMessage(actual, errorf1("does NOT have length “%v”", expected))
+	return
+}
+
+func lengthOf(value interface{}) (length int, err error) {
+	if list, ok := value.(*list.List); ok {
+		return list.Len(), nil
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.String, reflect.Map, reflect.Chan:
+		length = v.Len()
+	default:
+		err = Errorf("type error: expected a type with a length, but was “%v” of type “%T”", value, value)
+	}
+	return
+}
+
+// The expected length may be of any integer type.
+func isLength(length int, expected interface{}) (bool, error) {
+	switch v := reflect.ValueOf(expected); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == int64(length), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == uint64(length), nil
+	}
+	return false, Errorf("type error: expected an integer length, but was “%v” of type “%T”", expected, expected)
+}
+
+// The actual collection must be empty. Works with arrays, slices, strings,
+// maps and channels.
+func IsEmpty(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	length, err := lengthOf(actual)
+	if err != nil {
+		return
+	}
+
+	match = length == 0
+	pos = newMessage(actual, errorf0("is empty"))
+	neg = newMessage(actual, errorf0("is NOT empty"))
+	return
+}
+
+// The actual collection must have at least n elements. Works with arrays,
+// slices, strings, maps and channels.
+func HasAtLeastNElements(n int) Matcher {
+	return func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		length, err := lengthOf(actual)
+		if err != nil {
+			return
+		}
+
+		match = length >= n
+		pos = newMessage(actual, errorf2("has at least %v elements, but had %v", n, length))
+		neg = newMessage(actual, errorf2("does NOT have at least %v elements, but had %v", n, length))
+		return
+	}
+}
+
+// The actual collection must have at most n elements. Works with arrays,
+// slices, strings, maps and channels.
+func HasAtMostNElements(n int) Matcher {
+	return func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		length, err := lengthOf(actual)
+		if err != nil {
+			return
+		}
+
+		match = length <= n
+		pos = newMessage(actual, errorf2("has at most %v elements, but had %v", n, length))
+		neg = newMessage(actual, errorf2("does NOT have at most %v elements, but had %v", n, length))
+		return
+	}
+}
+
+// The actual collection must have exactly one element.
+func HasExactlyOneElement(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	length, err := lengthOf(actual)
+	if err != nil {
+		return
+	}
+
+	match = length == 1
+	pos = newMessage(actual, errorf1("has exactly one element, but had %v", length))
+	neg = newMessage(actual, errorf0("does NOT have exactly one element"))
+	return
+}
+
+// The actual collection must have exactly one element, and that element
+// must match the inner matcher, which is given the expected value unchanged.
+func HasExactlyOneElementMatching(inner Matcher) Matcher {
+	return func(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toArray(actual_)
+		if err != nil {
+			return
+		}
+		if len(actual) != 1 {
+			pos = newMessage(actual, errorf1("has exactly one element, but had %v", len(actual)))
+			neg = newMessage(actual, errorf0("does NOT have exactly one element"))
+			return
+		}
+
+		match, pos, neg, err = inner(actual[0], expected)
+		if err != nil {
+			return
+		}
+		neg = newMessage(actual, errorf1("does NOT have exactly one element which %v", expectationOf{pos}))
+		pos = newMessage(actual, errorf1("has exactly one element which %v", expectationOf{pos}))
+		return
+	}
+}
+
+// The actual string must start with the prefix.
+func HasPrefix(prefix string) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toString(actual_)
+		if err != nil {
+			return
+		}
+
+		match = strings.HasPrefix(actual, prefix)
+		pos = newMessage(actual, errorf1("has prefix “%v”", prefix))
+		neg = newMessage(actual, errorf1("does NOT have prefix “%v”", prefix))
+		return
+	}
+}
+
+// The actual string must end with the suffix.
+func HasSuffix(suffix string) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toString(actual_)
+		if err != nil {
+			return
+		}
+
+		match = strings.HasSuffix(actual, suffix)
+		pos = newMessage(actual, errorf1("has suffix “%v”", suffix))
+		neg = newMessage(actual, errorf1("does NOT have suffix “%v”", suffix))
+		return
+	}
+}
+
+// The actual string must contain the substring.
+func ContainsSubstring(substring string) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toString(actual_)
+		if err != nil {
+			return
+		}
+
+		match = strings.Contains(actual, substring)
+		pos = newMessage(actual, errorf1("contains substring “%v”", substring))
+		neg = newMessage(actual, errorf1("does NOT contain substring “%v”", substring))
+		return
+	}
+}
+
+// The actual string must match the regular expression.
+func MatchesRegexp(pattern string) Matcher {
+	re, compileErr := regexp.Compile(pattern)
+	if compileErr != nil {
+		return func(_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+			err = Errorf("illegal argument: invalid regexp “%v”: %v", pattern, compileErr)
+			return
+		}
+	}
+	return MatchesCompiledRegexp(re)
+}
+
+// The actual string must match the regular expression. Avoids compiling the
+// same regular expression again in tight loops.
+func MatchesCompiledRegexp(re *regexp.Regexp) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toString(actual_)
+		if err != nil {
+			return
+		}
+
+		match = re.MatchString(actual)
+		pos = newMessage(actual, errorf1("matches regexp “%v”", re))
+		neg = newMessage(actual, errorf1("does NOT match regexp “%v”", re))
+		return
+	}
+}
+
+// The actual string must match the shell glob pattern, using the syntax
+// of filepath.Match.
+func MatchesGlob(pattern string) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toString(actual_)
+		if err != nil {
+			return
+		}
+
+		match, matchErr := filepath.Match(pattern, actual)
+		if matchErr != nil {
+			err = Errorf("illegal argument: invalid glob “%v”: %v", pattern, matchErr)
+			return
+		}
+		pos = newMessage(actual, errorf1("matches glob “%v”", pattern))
+		neg = newMessage(actual, errorf1("does NOT match glob “%v”", pattern))
+		return
+	}
+}
+
+// The actual string must equal the expected string, ignoring case.
+func EqualsFold(actual_ interface{}, expected_ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toString(actual_)
+	if err != nil {
+		return
+	}
+	expected, err := toString(expected_)
+	if err != nil {
+		return
+	}
+
+	match = strings.EqualFold(actual, expected)
+	pos = newMessage(actual, errorf1("equals “%v” (case-insensitive)", expected))
+	neg = newMessage(actual, errorf1("does NOT equal “%v” (case-insensitive)", expected))
+	return
+}
+
+func toString(value interface{}) (result string, err error) {
+	result, ok := value.(string)
+	if !ok {
+		err = Errorf("type error: expected a string, but was “%v” of type “%T”", value, value)
+	}
+	return
+}
+
+// The actual function, of type func(), must panic when called.
+func Panics(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toFunc(actual_)
+	if err != nil {
+		return
+	}
+
+	e := recoverOnPanic(actual)
+	match = e != nil
+	pos = newMessage(describePanic(e), errorf0("panics"))
+	neg = newMessage(describePanic(e), errorf0("does NOT panic"))
+	return
+}
+
+// The actual function, of type func(), must panic with a value which equals
+// the expected value.
+func PanicsWith(expected interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toFunc(actual_)
+		if err != nil {
+			return
+		}
+
+		e := recoverOnPanic(actual)
+		match = e != nil && areEqual(e.Cause, expected)
+		pos = newMessage(describePanic(e), errorf1("panics with “%v”", expected))
+		neg = newMessage(describePanic(e), errorf1("does NOT panic with “%v”", expected))
+		return
+	}
+}
+
+// The actual function, of type func(), must panic with a value whose string
+// representation contains the substring.
+func PanicsWithMessage(substring string) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toFunc(actual_)
+		if err != nil {
+			return
+		}
+
+		e := recoverOnPanic(actual)
+		match = e != nil && strings.Contains(fmt.Sprintf("%v", e.Cause), substring)
+		pos = newMessage(describePanic(e), errorf1("panics with message containing “%v”", substring))
+		neg = newMessage(describePanic(e), errorf1("does NOT panic with message containing “%v”", substring))
+		return
+	}
+}
+
+// The actual function, of type func(), must panic with a value which matches
+// the inner matcher. The inner matcher is given the expected value unchanged.
+func PanicsMatching(inner Matcher) Matcher {
+	return func(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toFunc(actual_)
+		if err != nil {
+			return
+		}
+
+		e := recoverOnPanic(actual)
+		if e == nil {
+			pos = newMessage(describePanic(e), errorf0("panics"))
+			neg = newMessage(describePanic(e), errorf0("does NOT panic"))
+			return
+		}
+		match, pos, neg, err = inner(e.Cause, expected)
+		if err != nil {
+			return
+		}
+		pos = newMessage(describePanic(e), errorf1("panics with a value which %v", expectationOf{pos}))
+		neg = newMessage(describePanic(e), errorf1("panics with a value which %v", expectationOf{neg}))
+		return
+	}
+}
+
+// Calls the function and recovers from any panic. Returns true if the
+// function panicked, in which case the panic value is stored in out. Useful
+// for making more detailed expectations about the panic value:
+//
+//	var cause interface{}
+//	c.Expect(CapturePanic(func() { parse("") }, &cause), IsTrue)
+//	c.Expect(cause, Equals, ErrEmptyInput)
+func CapturePanic(fn func(), out *interface{}) bool {
+	e := recoverOnPanic(fn)
+	if e == nil {
+		return false
+	}
+	*out = e.Cause
+	return true
+}
+
+func toFunc(value interface{}) (result func(), err error) {
+	result, ok := value.(func())
+	if !ok {
+		err = Errorf("type error: expected a func(), but was “%v” of type “%T”", value, value)
+	}
+	return
+}
+
+func describePanic(e *exception) string {
+	if e == nil {
+		return "<no panic>"
+	}
+	return e.String()
+}
+
+// The actual error must be the target error or wrap it, as determined by
+// errors.Is.
+func IsError(target error) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toError(actual_)
+		if err != nil {
+			return
+		}
+
+		match = errors.Is(actual, target)
+		pos = newMessage(actual, errorf1("has “%v” in its error chain", target))
+		neg = newMessage(actual, errorf1("does NOT have “%v” in its error chain", target))
+		return
+	}
+}
+
+// The actual error's message must contain the substring.
+func HasErrorMessage(substring string) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toError(actual_)
+		if err != nil {
+			return
+		}
+
+		match = strings.Contains(actual.Error(), substring)
+		pos = newMessage(actual, errorf1("has error message containing “%v”", substring))
+		neg = newMessage(actual, errorf1("does NOT have error message containing “%v”", substring))
+		return
+	}
+}
+
+// The actual error must have an error of the target's type in its error
+// chain, as determined by errors.As. The target must be a non-nil pointer
+// to an error type or an interface type, and the matching error is
+// assigned to it.
+func IsErrorType(target interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toError(actual_)
+		if err != nil {
+			return
+		}
+		errorType, err := errorTypeOf(target)
+		if err != nil {
+			return
+		}
+
+		match = errors.As(actual, target)
+		pos = newMessage(actual, errorf1("has an error of type “%v” in its error chain", errorType))
+		neg = newMessage(actual, errorf1("does NOT have an error of type “%v” in its error chain", errorType))
+		return
+	}
+}
+
+var errorInterface = reflect.TypeOf((*error)(nil)).Elem()
+
+func errorTypeOf(target interface{}) (errorType reflect.Type, err error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		errorType = v.Type().Elem()
+		if errorType.Kind() == reflect.Interface || errorType.Implements(errorInterface) {
+			return
+		}
+	}
+	err = Errorf("type error: expected a pointer to an error type, but was “%v” of type “%T”", target, target)
+	return
+}
+
+func toError(value interface{}) (result error, err error) {
+	result, ok := value.(error)
+	if !ok {
+		err = Errorf("type error: expected an error, but was “%v” of type “%T”", value, value)
+	}
+	return
+}
+
+// The actual value must be of the same type as the expected value.
+func IsOfType(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	actualType, err := typeOf(actual)
+	if err != nil {
+		return
+	}
+	expectedType, err := typeOf(expected)
+	if err != nil {
+		return
+	}
+
+	match = actualType == expectedType
+	pos = newMessage(actualType, errorf1("is of type “%v”", expectedType))
+	neg = newMessage(actualType, errorf1("is NOT of type “%v”", expectedType))
+	return
+}
+
+// The actual value's type must have the given name, for example "*bytes.Buffer".
+// Useful when the expected type is not easily constructible.
+func IsOfTypeNamed(name string) Matcher {
+	return func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actualType, err := typeOf(actual)
+		if err != nil {
+			return
+		}
+
+		match = actualType.String() == name
+		pos = newMessage(actualType, errorf1("is of type “%v”", name))
+		neg = newMessage(actualType, errorf1("is NOT of type “%v”", name))
+		return
+	}
+}
+
+// The actual value must implement the expected interface, which is given
+// as a nil pointer to the interface type. For example:
+//
+//	c.Expect(buffer, Implements, (*io.Writer)(nil))
+func Implements(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	actualType, err := typeOf(actual)
+	if err != nil {
+		return
+	}
+	expectedType := reflect.TypeOf(expected)
+	if expectedType == nil || expectedType.Kind() != reflect.Ptr || expectedType.Elem().Kind() != reflect.Interface {
+		err = Errorf("type error: expected a pointer to an interface type, but was “%v” of type “%T”", expected, expected)
+		return
+	}
+	iface := expectedType.Elem()
+
+	match = actualType.Implements(iface)
+	pos = newMessage(actualType, errorf1("implements “%v”", iface))
+	neg = newMessage(actualType, errorf1("does NOT implement “%v”", iface))
+	return
+}
+
+func typeOf(value interface{}) (t reflect.Type, err error) {
+	t = reflect.TypeOf(value)
+	if t == nil {
+		err = Errorf("type error: expected a typed value, but was “%v”", value)
+	}
+	return
+}
+
+// The actual map must have the expected key.
+func HasKey(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toMap(actual_)
+	if err != nil {
+		return
+	}
+
+	_, match = findMapKey(actual, expected)
+	pos = newMessage(actual_, errorf1("has key “%v”", expected))
+	neg = newMessage(actual_, errorf1("does NOT have key “%v”", expected))
+	return
+}
+
+// The actual map must have the expected value for at least one key.
+func HasValue(actual_ interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
+	actual, err := toMap(actual_)
+	if err != nil {
+		return
+	}
+
+	for _, key := range actual.MapKeys() {
+		if areEqual(actual.MapIndex(key).Interface(), expected) {
+			match = true
+			break
+		}
+	}
+	pos = newMessage(actual_, errorf1("has value “%v”", expected))
+	neg = newMessage(actual_, errorf1("does NOT have value “%v”", expected))
+	return
+}
+
+// The actual map must have the key, and the key's value must equal the value.
+func HasEntry(key interface{}, value interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := toMap(actual_)
+		if err != nil {
+			return
+		}
+
+		neg = newMessage(actual_, errorf2("does NOT have entry “%v”: “%v”", key, value))
+		k, found := findMapKey(actual, key)
+		if !found {
+			pos = newMessage(actual_, errorf2("has entry “%v”: “%v”, but the key was not present", key, value))
+			return
+		}
+		actualValue := actual.MapIndex(k).Interface()
+		match = areEqual(actualValue, value)
+		pos = newMessage(actualValue, errorf2("has entry “%v”: “%v”, but the key's value was different", key, value))
+		return
+	}
+}
+
+func toMap(value interface{}) (result reflect.Value, err error) {
+	result = reflect.ValueOf(value)
+	if result.Kind() != reflect.Map {
+		err = Errorf("type error: expected a map, but was “%v” of type “%T”", value, value)
+	}
+	return
+}
+
+func findMapKey(m reflect.Value, needle interface{}) (key reflect.Value, found bool) {
+	for _, k := range m.MapKeys() {
+		if areEqual(k.Interface(), needle) {
+			return k, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// The actual value must be a struct, or a pointer to a struct, whose field
+// equals the expected value. Fields of embedded structs may also be used.
+// Gives an error if the field does not exist or is not exported.
+func HasField(name string, value interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		field, err := structField(actual_, name)
+		if err != nil {
+			return
+		}
+
+		actualValue := field.Interface()
+		match = areEqual(actualValue, value)
+		pos = newMessage(actualValue, errorf2("has field “%v”: “%v”", name, value))
+		neg = newMessage(actualValue, errorf2("does NOT have field “%v”: “%v”", name, value))
+		return
+	}
+}
+
+// The actual value must be a struct, or a pointer to a struct, whose field
+// is of the same type as the expected value. The type of an interface field
+// is the dynamic type of its value, unless the value is nil. Gives an error
+// if the field does not exist or is not exported.
+func HasFieldOfType(name string, value interface{}) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		field, err := structField(actual_, name)
+		if err != nil {
+			return
+		}
+		expectedType, err := typeOf(value)
+		if err != nil {
+			return
+		}
+
+		actualType := field.Type()
+		if field.Kind() == reflect.Interface && !field.IsNil() {
+			actualType = field.Elem().Type()
+		}
+		match = actualType.String() == expectedType.String()
+		pos = newMessage(actualType, errorf2("has field “%v” of type “%v”", name, expectedType))
+		neg = newMessage(actualType, errorf2("does NOT have field “%v” of type “%v”", name, expectedType))
+		return
+	}
+}
+
+func structField(value interface{}, name string) (field reflect.Value, err error) {
+	v, err := toStruct(value)
+	if err != nil {
+		return
+	}
+	f, found := v.Type().FieldByName(name)
+	if !found {
+		err = Errorf("illegal argument: “%T” has no field “%v”", value, name)
+		return
+	}
+	if !f.IsExported() {
+		err = Errorf("illegal argument: the field “%v” of “%T” is not exported", name, value)
+		return
+	}
+	field, e := v.FieldByIndexErr(f.Index)
+	if e != nil {
+		err = Errorf("illegal argument: cannot get the field “%v” of “%T”: %v", name, value, e)
+	}
+	return
+}
+
+func toStruct(value interface{}) (result reflect.Value, err error) {
+	result = reflect.ValueOf(value)
+	if result.Kind() == reflect.Ptr && !result.IsNil() {
+		result = result.Elem()
+	}
+	if result.Kind() != reflect.Struct {
+		err = Errorf("type error: expected a struct, but was “%v” of type “%T”", value, value)
+	}
+	return
+}
+
+// The condition must become true within the timeout. The condition is called
+// immediately and then once every interval, until it returns true or the
+// timeout has elapsed. The actual value is ignored, so nil may be used:
+//
+//	c.Expect(nil, Eventually(func() bool { return server.IsReady() }, time.Second, 10*time.Millisecond))
+func Eventually(condition func() bool, timeout time.Duration, interval time.Duration) Matcher {
+	return EventuallyContext(context.Background(), condition, timeout, interval)
+}
+
+// Same as Eventually, but stops polling with an error when the context
+// is cancelled.
+func EventuallyContext(ctx context.Context, condition func() bool, timeout time.Duration, interval time.Duration) Matcher {
+	return func(_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		match, elapsed, err := poll(ctx, condition, timeout, interval, true)
+		if err != nil {
+			return
+		}
+
+		result := fmt.Sprintf("%v after %v", match, elapsed)
+		pos = newMessage(result, errorf1("becomes true within %v", timeout))
+		neg = newMessage(result, errorf1("does NOT become true within %v", timeout))
+		return
+	}
+}
+
+// The condition must stay true for the whole duration. The condition is
+// called immediately and then once every interval, until it returns false
+// or the duration has elapsed. The actual value is ignored, so nil may be used:
+//
+//	c.Expect(nil, Consistently(func() bool { return cache.Has(key) }, time.Second, 10*time.Millisecond))
+func Consistently(condition func() bool, duration time.Duration, interval time.Duration) Matcher {
+	return ConsistentlyContext(context.Background(), condition, duration, interval)
+}
+
+// Same as Consistently, but stops polling with an error when the context
+// is cancelled.
+func ConsistentlyContext(ctx context.Context, condition func() bool, duration time.Duration, interval time.Duration) Matcher {
+	return func(_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		match, elapsed, err := poll(ctx, condition, duration, interval, false)
+		if err != nil {
+			return
+		}
+
+		result := fmt.Sprintf("%v after %v", match, elapsed)
+		if match {
+			pos = newMessage(result, errorf1("remains true for %v", duration))
+		} else {
+			pos = newMessage(result, errorf2("remains true for %v, but was false after %v", duration, elapsed.Truncate(time.Millisecond)))
+		}
+		neg = newMessage(result, errorf1("does NOT remain true for %v", duration))
+		return
+	}
+}
+
+// Calls the condition once every interval, until it returns the stop value
+// or the duration has elapsed. Returns the last result of the condition.
+// Polling happens in the calling goroutine, so that no goroutines are left
+// running after a cancellation.
+func poll(ctx context.Context, condition func() bool, duration time.Duration, interval time.Duration, stop bool) (result bool, elapsed time.Duration, err error) {
+	start := time.Now()
+	deadline := start.Add(duration)
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		if result = condition(); result == stop || !time.Now().Before(deadline) {
+			return result, time.Since(start), nil
+		}
+		select {
+		case <-ctx.Done():
+			return result, time.Since(start), Errorf("polling was cancelled: %v", ctx.Err())
+		case <-timer.C:
+			timer.Reset(interval)
+		}
+	}
+}
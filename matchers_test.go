@@ -6,11 +6,15 @@ package gospec
 
 import (
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"github.com/orfjackal/nanospec.go/src/nanospec"
 	"os"
+	"regexp"
+	"time"
 )
 
 func MatcherMessagesSpec(c nanospec.Context) {
@@ -35,6 +39,12 @@ func MatcherMessagesSpec(c nanospec.Context) {
 		m.Expect(666, DummyEquals, 1)
 		c.Expect(spy.LastError()).Equals("666 illegal value")
 	})
+	c.Specify("Messages with a fixed number of arguments are formatted the same way as with Errorf", func() {
+		c.Expect(errorf0("is <nil>").Error()).Equals(Errorf("is <nil>").Error())
+		c.Expect(errorf1("equals “%v”", 42).Error()).Equals(Errorf("equals “%v”", 42).Error())
+		c.Expect(errorf2("is %v and %v", 1, "x").Error()).Equals(Errorf("is %v and %v", 1, "x").Error())
+		c.Expect(errorf3("is %v, %v and %v", 1, "x", nil).Error()).Equals(Errorf("is %v, %v and %v", 1, "x", nil).Error())
+	})
 }
 
 func DummyEquals(actual interface{}, expected interface{}) (match bool, pos Message, neg Message, err error) {
@@ -74,25 +84,381 @@ func (this *SpyErrorLogger) Reset() {
 
 func MatchersSpec(c nanospec.Context) {
 
+	c.Specify("Matcher: And", func() {
+		c.Specify("matches when both matchers match", func() {
+			c.Expect(E(4, And(IsPositive, IsEven))).Matches(Succeeds)
+		})
+		c.Specify("fails with the first matcher's message when it fails", func() {
+			c.Expect(E(-3, And(IsPositive, IsEven))).Matches(FailsWithMessage(
+				"is positive",
+				"is NOT positive"))
+		})
+		c.Specify("fails with the second matcher's message when it fails", func() {
+			c.Expect(E(3, And(IsPositive, IsEven))).Matches(FailsWithMessage(
+				"is even",
+				"is NOT even"))
+		})
+		c.Specify("does not call the second matcher when the first fails", func() {
+			c.Expect(E(-3, And(IsPositive, DummyEquals), 666)).Matches(Fails)
+		})
+		c.Specify("reports errors", func() {
+			c.Expect(E("x", And(IsPositive, IsEven))).Matches(GivesError("type error: expected a number, but was “x” of type “string”"))
+			c.Expect(E(666, And(IsPositive, DummyEquals), 1)).Matches(GivesError("illegal value"))
+		})
+		c.Specify("can be negated", func() {
+			c.Expect(E(3, Not(And(IsPositive, IsEven)))).Matches(Succeeds)
+			c.Expect(E(4, Not(And(IsPositive, IsEven)))).Matches(FailsWithMessage(
+				"is NOT positive, or is NOT even",
+				"is even"))
+		})
+	})
+
+	c.Specify("Matcher: Or", func() {
+		c.Specify("matches when either matcher matches", func() {
+			c.Expect(E(nil, Or(IsNil, IsEmpty))).Matches(Succeeds)
+			c.Expect(E([]int{}, Or(IsNil, IsEmpty))).Matches(Succeeds)
+		})
+		c.Specify("fails with both matchers' messages when neither matches", func() {
+			c.Expect(E([]int{1}, Or(IsNil, IsEmpty))).Matches(FailsWithMessage(
+				"is <nil>, or is empty",
+				"is NOT empty"))
+		})
+		c.Specify("does not call the second matcher when the first matches", func() {
+			c.Expect(E(666, Or(IsPositive, DummyEquals), 1)).Matches(Succeeds)
+		})
+		c.Specify("reports errors", func() {
+			c.Expect(E(42, Or(IsNil, IsEmpty))).Matches(GivesError("type error: expected a type with a length, but was “42” of type “int”"))
+		})
+		c.Specify("can be negated", func() {
+			c.Expect(E([]int{1}, Not(Or(IsNil, IsEmpty)))).Matches(Succeeds)
+			c.Expect(E([]int{}, Not(Or(IsNil, IsEmpty)))).Matches(FailsWithMessage(
+				"is NOT empty",
+				"is empty"))
+		})
+	})
+
+	c.Specify("Matcher: AllOf", func() {
+		c.Specify("matches when all matchers match", func() {
+			c.Expect(E(4, AllOf(IsPositive, IsEven, IsLessThan), 10)).Matches(Succeeds)
+		})
+		c.Specify("fails with the messages of every failed matcher", func() {
+			c.Expect(E(-3, AllOf(IsPositive, IsOdd, IsEven))).Matches(FailsWithMessage(
+				"is positive, and is even",
+				"is NOT odd"))
+		})
+		c.Specify("matches when there are no matchers", func() {
+			c.Expect(E(-3, AllOf())).Matches(Succeeds)
+		})
+		c.Specify("delegates to the only matcher", func() {
+			c.Expect(E(-3, AllOf(IsPositive))).Matches(FailsWithMessage(
+				"is positive",
+				"is NOT positive"))
+		})
+		c.Specify("reports errors", func() {
+			c.Expect(E("x", AllOf(IsNil, IsPositive))).Matches(GivesError("type error: expected a number, but was “x” of type “string”"))
+		})
+		c.Specify("can be negated", func() {
+			c.Expect(E(3, Not(AllOf(IsPositive, IsEven)))).Matches(Succeeds)
+			c.Expect(E(4, Not(AllOf(IsPositive, IsEven)))).Matches(FailsWithMessage(
+				"is NOT positive, or is NOT even",
+				""))
+		})
+	})
+
+	c.Specify("Matcher: AnyOf", func() {
+		c.Specify("matches when any matcher matches", func() {
+			c.Expect(E("b", AnyOf(Equals, EqualsFold, HasPrefix("b")), "B")).Matches(Succeeds)
+		})
+		c.Specify("fails with the messages of every matcher when none match", func() {
+			c.Expect(E(-3, AnyOf(IsPositive, IsEven, IsZero))).Matches(FailsWithMessage(
+				"is positive, or is even, or is the zero value",
+				"is NOT positive, and is NOT even, and is NOT the zero value"))
+		})
+		c.Specify("does not call the matchers after the first match", func() {
+			c.Expect(E(666, AnyOf(IsPositive, DummyEquals), 1)).Matches(Succeeds)
+		})
+		c.Specify("never matches when there are no matchers", func() {
+			c.Expect(E(-3, AnyOf())).Matches(FailsWithMessage(
+				"matches any of the matchers, but AnyOf was given no matchers",
+				"does NOT match any of the matchers"))
+			c.Expect(E(-3, Not(AnyOf()))).Matches(Succeeds)
+		})
+		c.Specify("delegates to the only matcher", func() {
+			c.Expect(E(-3, AnyOf(IsPositive))).Matches(FailsWithMessage(
+				"is positive",
+				"is NOT positive"))
+		})
+		c.Specify("reports errors", func() {
+			c.Expect(E("x", AnyOf(IsNil, IsPositive))).Matches(GivesError("type error: expected a number, but was “x” of type “string”"))
+		})
+		c.Specify("can be negated", func() {
+			c.Expect(E(-3, Not(AnyOf(IsPositive, IsEven)))).Matches(Succeeds)
+			c.Expect(E(3, Not(AnyOf(IsEven, IsPositive)))).Matches(FailsWithMessage(
+				"is NOT positive",
+				"is positive"))
+		})
+	})
+
+	c.Specify("Matcher: Transform", func() {
+		valueOf := func(v interface{}) interface{} {
+			return v.(DummyStruct).value
+		}
+
+		c.Specify("matches the transformed value", func() {
+			c.Expect(E(DummyStruct{42, 1}, Transform(valueOf, Equals), 42)).Matches(Succeeds)
+		})
+		c.Specify("fails with the inner matcher's message and the original value", func() {
+			c.Expect(E(DummyStruct{3, 1}, Transform(valueOf, Equals), 5)).Matches(FailsWithMessage(
+				"equals “5”, after transforming “DummyStruct3”",
+				"does NOT equal “5”, after transforming “DummyStruct3”"))
+
+			_, pos, _, _ := Transform(valueOf, Equals)(DummyStruct{3, 1}, 5)
+			c.Expect(pos.Actual()).Equals(3)
+		})
+		c.Specify("reports panics in the transformation as errors", func() {
+			c.Expect(E("hello", Transform(valueOf, Equals), 5)).Matches(GivesError(
+				"transformation of “hello” failed: panic: interface conversion: interface {} is string, not gospec.DummyStruct"))
+		})
+		c.Specify("reports errors of the inner matcher", func() {
+			c.Expect(E(DummyStruct{3, 1}, Transform(valueOf, IsEmpty))).Matches(GivesError(
+				"type error: expected a type with a length, but was “3” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: WithMessage", func() {
+		isValidPrice := WithMessage("is a valid price", "is an invalid price", IsGreaterThan)
+
+		c.Expect(E(5, isValidPrice, 0)).Matches(Succeeds)
+		c.Expect(E(-5, isValidPrice, 0)).Matches(FailsWithMessage(
+			"is a valid price",
+			"is an invalid price"))
+
+		c.Specify("the messages may contain the actual value", func() {
+			c.Expect(E(-5, WithMessage("price %v is valid", "price %v (%[1]v) is invalid", IsPositive))).Matches(FailsWithMessage(
+				"price -5 is valid",
+				"price -5 (-5) is invalid"))
+		})
+		c.Specify("the actual value may be formatted with any verb", func() {
+			c.Expect(E(-5, WithMessage("price %d is valid", "price %x is invalid", IsPositive))).Matches(FailsWithMessage(
+				"price -5 is valid",
+				"price -5 is invalid"))
+			c.Expect(E("x", WithMessage("name %q is valid", "name %s is invalid", HasPrefix("a")))).Matches(FailsWithMessage(
+				"name \"x\" is valid",
+				"name x is invalid"))
+		})
+		c.Specify("the messages may contain a literal percent sign", func() {
+			c.Expect(E(-5, WithMessage("discount %v%% is valid", "100%% invalid", IsPositive))).Matches(FailsWithMessage(
+				"discount -5% is valid",
+				"100% invalid"))
+			c.Expect(E(-5, WithMessage("%%v is not replaced", "%%%v", IsPositive))).Matches(FailsWithMessage(
+				"%v is not replaced",
+				"%-5"))
+		})
+		c.Specify("errors are reported unchanged", func() {
+			c.Expect(E("x", isValidPrice, 0)).Matches(GivesError("type error: expected a number, but was “x” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: Named", func() {
+		priceValidator := Named("PriceValidator", IsPositive)
+
+		c.Expect(E(5, priceValidator)).Matches(Succeeds)
+		c.Expect(E(-5, priceValidator)).Matches(FailsWithMessage(
+			"[PriceValidator] is positive",
+			"[PriceValidator] is NOT positive"))
+
+		c.Specify("errors are reported unchanged", func() {
+			c.Expect(E("x", priceValidator)).Matches(GivesError("type error: expected a number, but was “x” of type “string”"))
+		})
+	})
+
 	c.Specify("Matcher: Equals", func() {
 		c.Specify("strings", func() {
-			c.Expect(E("apple", Equals, "apple")).Matches(Passes)
+			c.Expect(E("apple", Equals, "apple")).Matches(Succeeds)
 			c.Expect(E("apple", Equals, "orange")).Matches(FailsWithMessage(
 				"equals “orange”",
 				"does NOT equal “orange”"))
 		})
 		c.Specify("ints", func() {
-			c.Expect(E(42, Equals, 42)).Matches(Passes)
+			c.Expect(E(42, Equals, 42)).Matches(Succeeds)
 			c.Expect(E(42, Equals, 999)).Matches(Fails)
 		})
 		c.Specify("structs", func() {
-			c.Expect(E(DummyStruct{42, 1}, Equals, DummyStruct{42, 2})).Matches(Passes)
+			c.Expect(E(DummyStruct{42, 1}, Equals, DummyStruct{42, 2})).Matches(Succeeds)
 			c.Expect(E(DummyStruct{42, 1}, Equals, DummyStruct{999, 2})).Matches(Fails)
 		})
 		c.Specify("struct pointers", func() {
-			c.Expect(E(&DummyStruct{42, 1}, Equals, &DummyStruct{42, 2})).Matches(Passes)
+			c.Expect(E(&DummyStruct{42, 1}, Equals, &DummyStruct{42, 2})).Matches(Succeeds)
 			c.Expect(E(&DummyStruct{42, 1}, Equals, &DummyStruct{999, 2})).Matches(Fails)
 		})
+		c.Specify("the failure message of structs contains a diff of the values", func() {
+			expected := diffDummyMeta{30}
+			_, pos, _, _ := Equals(diffDummyMeta{25}, expected)
+			c.Expect(pos.Expectation()).Equals(`equals “{30}”
+--- expected
++++ actual
+  gospec.diffDummyMeta{
+-   Age:30,
++   Age:25,
+  }`)
+		})
+		c.Specify("the failure message of single-line values does not contain a diff", func() {
+			_, pos, _, _ := Equals(1, 2)
+			c.Expect(pos.Expectation()).Equals("equals “2”")
+		})
+	})
+
+	c.Specify("Matcher: DeepEquals", func() {
+		type Person struct {
+			Name string
+			Age  int
+		}
+
+		c.Specify("slices", func() {
+			c.Expect(E([]int{1, 2, 3}, DeepEquals, []int{1, 2, 3})).Matches(Succeeds)
+			c.Expect(E([]int{1, 2, 3}, DeepEquals, []int{1, 2})).Matches(FailsWithMessage(
+				"deep equals “[]int{1, 2}”",
+				"does NOT deep equal “[]int{1, 2}”"))
+		})
+		c.Specify("maps", func() {
+			c.Expect(E(map[string]int{"a": 1, "b": 2}, DeepEquals, map[string]int{"b": 2, "a": 1})).Matches(Succeeds)
+			c.Expect(E(map[string]int{"a": 1}, DeepEquals, map[string]int{"a": 2})).Matches(Fails)
+		})
+		c.Specify("nested structs", func() {
+			type Team struct {
+				Members []Person
+			}
+			c.Expect(E(Team{[]Person{{"Alice", 30}}}, DeepEquals, Team{[]Person{{"Alice", 30}}})).Matches(Succeeds)
+			c.Expect(E(Team{[]Person{{"Alice", 30}}}, DeepEquals, Team{[]Person{{"Bob", 25}}})).Matches(Fails)
+		})
+		c.Specify("pointers are compared by value", func() {
+			c.Expect(E(&Person{"Alice", 30}, DeepEquals, &Person{"Alice", 30})).Matches(Succeeds)
+			c.Expect(E(&Person{"Alice", 30}, DeepEquals, &Person{"Bob", 25})).Matches(Fails)
+		})
+		c.Specify("the actual value is reported in Go syntax", func() {
+			_, pos, _, _ := DeepEquals(Person{"Bob", 25}, Person{"Alice", 30})
+			c.Expect(fmt.Sprint(pos.Actual())).Equals(`gospec.Person{Name:"Bob", Age:25}`)
+		})
+	})
+
+	c.Specify("Matcher: DeepEqualsIgnoring", func() {
+		expected := diffDummy{"Alice", []string{"admin"}, diffDummyMeta{30}}
+		actual := diffDummy{"Bob", []string{"admin"}, diffDummyMeta{25}}
+
+		c.Expect(E(actual, DeepEqualsIgnoring("Name", "Meta.Age"), expected)).Matches(Succeeds)
+		c.Expect(E(actual, DeepEqualsIgnoring("Name"), expected)).Matches(FailsWithMessage(
+			`deep equals “gospec.diffDummy{Name:"Alice", Tags:[]string{"admin"}, Meta:gospec.diffDummyMeta{Age:30}}”, ignoring “[Name]”`,
+			`does NOT deep equal “gospec.diffDummy{Name:"Alice", Tags:[]string{"admin"}, Meta:gospec.diffDummyMeta{Age:30}}”, ignoring “[Name]”`))
+		c.Expect(E(actual, DeepEqualsIgnoring("Meta"), expected)).Matches(Fails)
+
+		c.Specify("without fields it is the same as DeepEquals", func() {
+			c.Expect(E(expected, DeepEqualsIgnoring(), expected)).Matches(Succeeds)
+			c.Expect(E(actual, DeepEqualsIgnoring(), expected)).Matches(Fails)
+			c.Expect(E([]int{1}, DeepEqualsIgnoring(), []int{1})).Matches(Succeeds)
+		})
+		c.Specify("a whole nested struct can be ignored", func() {
+			c.Expect(E(actual, DeepEqualsIgnoring("Name", "Meta"), expected)).Matches(Succeeds)
+		})
+		c.Specify("pointers to structs", func() {
+			c.Expect(E(&actual, DeepEqualsIgnoring("Name", "Meta.Age"), &expected)).Matches(Succeeds)
+			c.Expect(E(&actual, DeepEqualsIgnoring("Name"), &expected)).Matches(Fails)
+		})
+		c.Specify("the original values are not modified", func() {
+			a := &DummyUser{Name: "Bob", Friend: &DummyUser{Name: "Carol"}}
+			e := &DummyUser{Name: "Bob", Friend: &DummyUser{Name: "Dave"}}
+			c.Expect(E(a, DeepEqualsIgnoring("Friend.Name"), e)).Matches(Succeeds)
+			c.Expect(a.Friend.Name).Equals("Carol")
+			c.Expect(e.Friend.Name).Equals("Dave")
+		})
+		c.Specify("fields of embedded structs", func() {
+			a := DummyUserWithPointer{&DummyUserMeta{1}}
+			e := DummyUserWithPointer{&DummyUserMeta{2}}
+			c.Expect(E(a, DeepEqualsIgnoring("ID"), e)).Matches(Succeeds)
+			c.Expect(a.ID).Equals(1)
+		})
+		c.Specify("nil pointers along the path are left as they are", func() {
+			c.Expect(E(DummyUser{}, DeepEqualsIgnoring("Friend.Name"), DummyUser{})).Matches(Succeeds)
+			c.Expect(E(DummyUserWithPointer{}, DeepEqualsIgnoring("ID"), DummyUserWithPointer{})).Matches(Succeeds)
+		})
+		c.Specify("cannot ignore missing fields behind nil pointers", func() {
+			c.Expect(E(DummyUser{}, DeepEqualsIgnoring("Friend.Typo"), DummyUser{})).Matches(GivesError(
+				"illegal argument: “gospec.DummyUser” has no field “Friend.Typo”"))
+			c.Expect(E(DummyUser{}, DeepEqualsIgnoring("Friend.password"), DummyUser{})).Matches(GivesError(
+				"illegal argument: the field “Friend.password” of “gospec.DummyUser” is not exported"))
+			c.Expect(E(DummyUserWithPointer{}, DeepEqualsIgnoring("Typo"), DummyUserWithPointer{})).Matches(GivesError(
+				"illegal argument: “gospec.DummyUserWithPointer” has no field “Typo”"))
+		})
+		c.Specify("cannot ignore missing fields", func() {
+			c.Expect(E(actual, DeepEqualsIgnoring("Meta.Height"), expected)).Matches(GivesError(
+				"illegal argument: “gospec.diffDummy” has no field “Meta.Height”"))
+			c.Expect(E(actual, DeepEqualsIgnoring("Name.Length"), expected)).Matches(GivesError(
+				"illegal argument: “gospec.diffDummy” has no field “Name.Length”"))
+		})
+		c.Specify("cannot ignore unexported fields", func() {
+			c.Expect(E(DummyUser{}, DeepEqualsIgnoring("password"), DummyUser{})).Matches(GivesError(
+				"illegal argument: the field “password” of “gospec.DummyUser” is not exported"))
+		})
+		c.Specify("cannot ignore fields of non-structs", func() {
+			c.Expect(E("Bob", DeepEqualsIgnoring("Name"), "Bob")).Matches(GivesError(
+				"type error: expected a struct, but was “Bob” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: DeepEqualsWithDiff", func() {
+		expected := diffDummy{"Alice", []string{"admin"}, diffDummyMeta{30}}
+		actual := diffDummy{"Alice", []string{"admin"}, diffDummyMeta{25}}
+
+		c.Expect(E(expected, DeepEqualsWithDiff, expected)).Matches(Succeeds)
+		c.Expect(E(actual, DeepEqualsWithDiff, expected)).Matches(Fails)
+
+		c.Specify("the failure message contains a diff of the values", func() {
+			_, pos, _, _ := DeepEqualsWithDiff(actual, expected)
+			c.Expect(pos.Expectation()).Equals(`deep equals “gospec.diffDummy{Name:"Alice", Tags:[]string{"admin"}, Meta:gospec.diffDummyMeta{Age:30}}”
+--- expected
++++ actual
+  gospec.diffDummy{
+    Name:"Alice",
+    Tags:[]string{
+      "admin",
+    },
+    Meta:gospec.diffDummyMeta{
+-     Age:30,
++     Age:25,
+    },
+  }`)
+		})
+		c.Specify("the negative failure message does not contain a diff", func() {
+			_, _, neg, _ := DeepEqualsWithDiff(expected, expected)
+			c.Expect(neg.Expectation()).Equals(`does NOT deep equal “gospec.diffDummy{Name:"Alice", Tags:[]string{"admin"}, Meta:gospec.diffDummyMeta{Age:30}}”`)
+		})
+	})
+
+	c.Specify("Matcher: IsOneOf", func() {
+		c.Expect(E("b", IsOneOf("a", "b", "c"))).Matches(Succeeds)
+		c.Expect(E("d", IsOneOf("a", "b", "c"))).Matches(FailsWithMessage(
+			"is one of “[a b c]”",
+			"is NOT one of “[a b c]”"))
+
+		c.Specify("never matches when there are no values", func() {
+			c.Expect(E("a", IsOneOf())).Matches(FailsWithMessage(
+				"is one of “[]”",
+				"is NOT one of “[]”"))
+			c.Expect(E(nil, IsOneOf())).Matches(Fails)
+		})
+		c.Specify("the values may contain nil", func() {
+			c.Expect(E(nil, IsOneOf(1, nil))).Matches(Succeeds)
+			c.Expect(E(0, IsOneOf(1, nil))).Matches(Fails)
+		})
+		c.Specify("values are compared using the Equality interface", func() {
+			c.Expect(E(DummyStruct{1, 1}, IsOneOf(DummyStruct{1, 2}, DummyStruct{2, 1}))).Matches(Succeeds)
+			c.Expect(E(DummyStruct{3, 1}, IsOneOf(DummyStruct{1, 2}, DummyStruct{2, 1}))).Matches(Fails)
+		})
+		c.Specify("works with Not", func() {
+			c.Expect(E("c", Not(IsOneOf("a", "b")))).Matches(Succeeds)
+			c.Expect(E("a", Not(IsOneOf("a", "b")))).Matches(FailsWithMessage(
+				"is NOT one of “[a b]”",
+				"is one of “[a b]”"))
+		})
 	})
 
 	c.Specify("Matcher: IsSame", func() {
@@ -100,7 +466,7 @@ func MatchersSpec(c nanospec.Context) {
 		a2 := a1
 		b := new(os.File)
 
-		c.Expect(E(a1, IsSame, a2)).Matches(Passes)
+		c.Expect(E(a1, IsSame, a2)).Matches(Succeeds)
 		c.Expect(E(a1, IsSame, b)).Matches(FailsWithMessage(
 			fmt.Sprintf("is same as “%v”", b),
 			fmt.Sprintf("is NOT same as “%v”", b)))
@@ -112,8 +478,8 @@ func MatchersSpec(c nanospec.Context) {
 	})
 
 	c.Specify("Matcher: IsNil", func() {
-		c.Expect(E(nil, IsNil)).Matches(Passes)         // interface value nil
-		c.Expect(E((*int)(nil), IsNil)).Matches(Passes) // typed pointer nil inside an interface value
+		c.Expect(E(nil, IsNil)).Matches(Succeeds)         // interface value nil
+		c.Expect(E((*int)(nil), IsNil)).Matches(Succeeds) // typed pointer nil inside an interface value
 		c.Expect(E(new(int), IsNil)).Matches(Fails)
 		c.Expect(E(1, IsNil)).Matches(FailsWithMessage(
 			"is <nil>",
@@ -121,14 +487,14 @@ func MatchersSpec(c nanospec.Context) {
 	})
 
 	c.Specify("Matcher: IsTrue", func() {
-		c.Expect(E(true, IsTrue)).Matches(Passes)
+		c.Expect(E(true, IsTrue)).Matches(Succeeds)
 		c.Expect(E(false, IsTrue)).Matches(FailsWithMessage(
 			"is <true>",
 			"is NOT <true>"))
 	})
 
 	c.Specify("Matcher: IsFalse", func() {
-		c.Expect(E(false, IsFalse)).Matches(Passes)
+		c.Expect(E(false, IsFalse)).Matches(Succeeds)
 		c.Expect(E(true, IsFalse)).Matches(FailsWithMessage(
 			"is <false>",
 			"is NOT <false>"))
@@ -137,36 +503,313 @@ func MatchersSpec(c nanospec.Context) {
 	c.Specify("Matcher: Satisfies", func() {
 		value := 42
 
-		c.Expect(E(value, Satisfies, value < 100)).Matches(Passes)
+		c.Expect(E(value, Satisfies, value < 100)).Matches(Succeeds)
 		c.Expect(E(value, Satisfies, value > 100)).Matches(FailsWithMessage(
 			"satisfies the criteria",
 			"does NOT satisfy the criteria"))
 	})
 
+	c.Specify("Matcher: Passes", func() {
+		isSmall := func(actual interface{}) bool { return actual.(int) < 100 }
+
+		c.Expect(E(42, Passes(isSmall))).Matches(Succeeds)
+		c.Expect(E(420, Passes(isSmall))).Matches(FailsWithMessage(
+			"passes the predicate",
+			"does NOT pass the predicate"))
+
+		c.Specify("the predicate is called exactly once per expectation", func() {
+			calls := 0
+			counting := func(actual interface{}) bool {
+				calls++
+				return isSmall(actual)
+			}
+			runSpec(func(c Context) {
+				c.Expect(42, Passes(counting))
+				c.Expect(420, Passes(counting))
+				c.Expect(420, Not(Passes(counting)))
+			})
+			c.Expect(calls).Equals(3)
+		})
+	})
+
 	c.Specify("Matcher: IsWithin", func() {
 		value := float64(3.141)
 		pi := float64(math.Pi)
 
-		c.Expect(E(value, IsWithin(0.001), pi)).Matches(Passes)
+		c.Expect(E(value, IsWithin(0.001), pi)).Matches(Succeeds)
 		c.Expect(E(value, IsWithin(0.0001), pi)).Matches(FailsWithMessage(
 			"is within 3.141592653589793 ± 0.0001",
 			"is NOT within 3.141592653589793 ± 0.0001"))
 
-		c.Specify("cannot compare ints", func() {
-			value := int(3)
-			pi := float64(math.Pi)
+		c.Specify("ints and other numeric types", func() {
+			c.Expect(E(int(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(pi, IsWithin(0.2), int(3))).Matches(Succeeds)
+			c.Expect(E(int8(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(int16(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(int32(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(int64(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(uint(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(uint8(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(uint16(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(uint32(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(uint64(3), IsWithin(0.2), pi)).Matches(Succeeds)
+			c.Expect(E(float32(3.141), IsWithin(0.001), pi)).Matches(Succeeds)
+			c.Expect(E(int(3), IsWithin(0.1), pi)).Matches(Fails)
+		})
+		c.Specify("cannot compare non-numeric values", func() {
+			c.Expect(E("3", IsWithin(0.001), pi)).Matches(GivesError("type error: expected a number, but was “3” of type “string”"))
+			c.Expect(E(pi, IsWithin(0.001), "3")).Matches(GivesError("type error: expected a number, but was “3” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: IsWithinRelative", func() {
+		c.Expect(E(1000500, IsWithinRelative(0.001), 1000000)).Matches(Succeeds)
+		c.Expect(E(999500, IsWithinRelative(0.001), 1000000)).Matches(Succeeds)
+		c.Expect(E(-1000500, IsWithinRelative(0.001), -1000000)).Matches(Succeeds)
+		c.Expect(E(1002000, IsWithinRelative(0.001), 1000000)).Matches(FailsWithMessage(
+			"is within 1e+06 ± 0.1%",
+			"is NOT within 1e+06 ± 0.1%"))
+
+		c.Specify("an expected value of zero is compared using an absolute delta", func() {
+			c.Expect(E(0.0005, IsWithinRelative(0.001), 0)).Matches(Succeeds)
+			c.Expect(E(0.002, IsWithinRelative(0.001), 0)).Matches(Fails)
+		})
+		c.Specify("cannot compare non-numeric values", func() {
+			c.Expect(E("3", IsWithinRelative(0.001), 3)).Matches(GivesError("type error: expected a number, but was “3” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: IsGreaterThan", func() {
+		c.Expect(E(5, IsGreaterThan, 3)).Matches(Succeeds)
+		c.Expect(E(3, IsGreaterThan, 3)).Matches(Fails)
+		c.Expect(E(3, IsGreaterThan, 5)).Matches(FailsWithMessage(
+			"is greater than “5”",
+			"is NOT greater than “5”"))
+	})
+
+	c.Specify("Matcher: IsGreaterThanOrEqualTo", func() {
+		c.Expect(E(5, IsGreaterThanOrEqualTo, 3)).Matches(Succeeds)
+		c.Expect(E(3, IsGreaterThanOrEqualTo, 3)).Matches(Succeeds)
+		c.Expect(E(3, IsGreaterThanOrEqualTo, 5)).Matches(FailsWithMessage(
+			"is greater than or equal to “5”",
+			"is NOT greater than or equal to “5”"))
+	})
+
+	c.Specify("Matcher: IsLessThan", func() {
+		c.Expect(E(3, IsLessThan, 5)).Matches(Succeeds)
+		c.Expect(E(3, IsLessThan, 3)).Matches(Fails)
+		c.Expect(E(5, IsLessThan, 3)).Matches(FailsWithMessage(
+			"is less than “3”",
+			"is NOT less than “3”"))
+	})
+
+	c.Specify("Matcher: IsLessThanOrEqualTo", func() {
+		c.Expect(E(3, IsLessThanOrEqualTo, 5)).Matches(Succeeds)
+		c.Expect(E(3, IsLessThanOrEqualTo, 3)).Matches(Succeeds)
+		c.Expect(E(5, IsLessThanOrEqualTo, 3)).Matches(FailsWithMessage(
+			"is less than or equal to “3”",
+			"is NOT less than or equal to “3”"))
+	})
+
+	c.Specify("Matcher: IsBetween", func() {
+		c.Expect(E(37.0, IsBetween(36.0, 38.0))).Matches(Succeeds)
+		c.Expect(E(36.0, IsBetween(36.0, 38.0))).Matches(Succeeds)
+		c.Expect(E(38, IsBetween(36.0, 38.0))).Matches(Succeeds)
+		c.Expect(E(39.5, IsBetween(36.0, 38.0))).Matches(FailsWithMessage(
+			"is between 36 and 38",
+			"is NOT between 36 and 38"))
+
+		c.Specify("cannot compare non-numeric values", func() {
+			c.Expect(E("37", IsBetween(36.0, 38.0))).Matches(GivesError("type error: expected a number, but was “37” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: IsBetweenExclusive", func() {
+		c.Expect(E(37.0, IsBetweenExclusive(36.0, 38.0))).Matches(Succeeds)
+		c.Expect(E(36.0, IsBetweenExclusive(36.0, 38.0))).Matches(Fails)
+		c.Expect(E(38, IsBetweenExclusive(36.0, 38.0))).Matches(Fails)
+		c.Expect(E(39.5, IsBetweenExclusive(36.0, 38.0))).Matches(FailsWithMessage(
+			"is between 36 and 38, exclusive",
+			"is NOT between 36 and 38, exclusive"))
+
+		c.Specify("cannot compare non-numeric values", func() {
+			c.Expect(E("37", IsBetweenExclusive(36.0, 38.0))).Matches(GivesError("type error: expected a number, but was “37” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: IsPositive", func() {
+		c.Expect(E(5, IsPositive)).Matches(Succeeds)
+		c.Expect(E(0.1, IsPositive)).Matches(Succeeds)
+		c.Expect(E(0, IsPositive)).Matches(Fails)
+		c.Expect(E(-3, IsPositive)).Matches(FailsWithMessage(
+			"is positive",
+			"is NOT positive"))
+	})
+
+	c.Specify("Matcher: IsNegative", func() {
+		c.Expect(E(-5, IsNegative)).Matches(Succeeds)
+		c.Expect(E(-0.1, IsNegative)).Matches(Succeeds)
+		c.Expect(E(0, IsNegative)).Matches(Fails)
+		c.Expect(E(3, IsNegative)).Matches(FailsWithMessage(
+			"is negative",
+			"is NOT negative"))
+	})
+
+	c.Specify("Matcher: IsNonNegative", func() {
+		c.Expect(E(5, IsNonNegative)).Matches(Succeeds)
+		c.Expect(E(0.1, IsNonNegative)).Matches(Succeeds)
+		c.Expect(E(0, IsNonNegative)).Matches(Succeeds)
+		c.Expect(E(uint(0), IsNonNegative)).Matches(Succeeds)
+		c.Expect(E(-0.1, IsNonNegative)).Matches(FailsWithMessage(
+			"is non-negative",
+			"is NOT non-negative"))
+	})
+
+	c.Specify("Matcher: IsZero", func() {
+		c.Expect(E(0, IsZero)).Matches(Succeeds)
+		c.Expect(E(uint8(0), IsZero)).Matches(Succeeds)
+		c.Expect(E(0.0, IsZero)).Matches(Succeeds)
+		c.Expect(E(5, IsZero)).Matches(FailsWithMessage(
+			"is the zero value",
+			"is NOT the zero value"))
+
+		c.Specify("other comparable values", func() {
+			c.Expect(E(nil, IsZero)).Matches(Succeeds)
+			c.Expect(E("", IsZero)).Matches(Succeeds)
+			c.Expect(E("foo", IsZero)).Matches(Fails)
+			c.Expect(E((*int)(nil), IsZero)).Matches(Succeeds)
+			c.Expect(E(new(int), IsZero)).Matches(Fails)
+			c.Expect(E(DummyStruct{0, 1}, IsZero)).Matches(Succeeds)
+			c.Expect(E(DummyStruct{42, 0}, IsZero)).Matches(Fails)
+		})
+		c.Specify("cannot compare non-comparable values", func() {
+			c.Expect(E([]int{}, IsZero)).Matches(GivesError("type error: expected a comparable value, but was “[]” of type “[]int”"))
+		})
+	})
+
+	c.Specify("Matcher: IsNaN", func() {
+		c.Expect(E(math.NaN(), IsNaN)).Matches(Succeeds)
+		c.Expect(E(float32(math.NaN()), IsNaN)).Matches(Succeeds)
+		c.Expect(E(math.Inf(1), IsNaN)).Matches(Fails)
+		c.Expect(E(3.14, IsNaN)).Matches(FailsWithMessage(
+			"is NaN",
+			"is NOT NaN"))
+	})
 
-			c.Expect(E(value, IsWithin(0.001), pi)).Matches(GivesError("type error: expected a float, but was “3” of type “int”"))
-			c.Expect(E(pi, IsWithin(0.001), value)).Matches(GivesError("type error: expected a float, but was “3” of type “int”"))
+	c.Specify("Matcher: IsInfinite", func() {
+		c.Expect(E(math.Inf(1), IsInfinite)).Matches(Succeeds)
+		c.Expect(E(math.Inf(-1), IsInfinite)).Matches(Succeeds)
+		c.Expect(E(math.Inf(1), IsInfinite, 0)).Matches(Succeeds)
+		c.Expect(E(math.NaN(), IsInfinite)).Matches(Fails)
+		c.Expect(E(3.14, IsInfinite)).Matches(FailsWithMessage(
+			"is ±Inf",
+			"is NOT ±Inf"))
+
+		c.Specify("with a sign", func() {
+			c.Expect(E(math.Inf(1), IsInfinite, 1)).Matches(Succeeds)
+			c.Expect(E(math.Inf(-1), IsInfinite, -1)).Matches(Succeeds)
+			c.Expect(E(math.Inf(-1), IsInfinite, 1)).Matches(FailsWithMessage(
+				"is +Inf",
+				"is NOT +Inf"))
+			c.Expect(E(math.Inf(1), IsInfinite, -1)).Matches(FailsWithMessage(
+				"is -Inf",
+				"is NOT -Inf"))
+		})
+	})
+
+	c.Specify("Matcher: IsEven", func() {
+		c.Expect(E(4, IsEven)).Matches(Succeeds)
+		c.Expect(E(0, IsEven)).Matches(Succeeds)
+		c.Expect(E(-2, IsEven)).Matches(Succeeds)
+		c.Expect(E(-3, IsEven)).Matches(Fails)
+		c.Expect(E(7, IsEven)).Matches(FailsWithMessage(
+			"is even",
+			"is NOT even"))
+	})
+
+	c.Specify("Matcher: IsOdd", func() {
+		c.Expect(E(7, IsOdd)).Matches(Succeeds)
+		c.Expect(E(-3, IsOdd)).Matches(Succeeds)
+		c.Expect(E(0, IsOdd)).Matches(Fails)
+		c.Expect(E(4, IsOdd)).Matches(FailsWithMessage(
+			"is odd",
+			"is NOT odd"))
+	})
+
+	c.Specify("Parity matchers accept all integer types", func() {
+		c.Expect(E(int8(-3), IsOdd)).Matches(Succeeds)
+		c.Expect(E(int16(4), IsEven)).Matches(Succeeds)
+		c.Expect(E(int32(5), IsOdd)).Matches(Succeeds)
+		c.Expect(E(int64(6), IsEven)).Matches(Succeeds)
+		c.Expect(E(uint(7), IsOdd)).Matches(Succeeds)
+		c.Expect(E(uint8(8), IsEven)).Matches(Succeeds)
+		c.Expect(E(uint16(9), IsOdd)).Matches(Succeeds)
+		c.Expect(E(uint32(10), IsEven)).Matches(Succeeds)
+		c.Expect(E(uint64(math.MaxUint64), IsOdd)).Matches(Succeeds)
+		c.Expect(E(uint64(math.MaxUint64-1), IsEven)).Matches(Succeeds)
+		c.Expect(E(4.0, IsEven)).Matches(Succeeds)
+
+		c.Specify("also above 2^53, where float64 loses precision", func() {
+			c.Expect(E(int64(9007199254740993), IsOdd)).Matches(Succeeds)
+			c.Expect(E(int64(9007199254740993), IsEven)).Matches(Fails)
+			c.Expect(E(int64(-9007199254740993), IsOdd)).Matches(Succeeds)
+			c.Expect(E(int64(math.MinInt64), IsEven)).Matches(Succeeds)
+			c.Expect(E(uint64(9007199254740993), IsOdd)).Matches(Succeeds)
+		})
+
+		c.Specify("but not fractions", func() {
+			c.Expect(E(4.5, IsEven)).Matches(GivesError("type error: expected an integer, but was “4.5” of type “float64”"))
+			c.Expect(E(4.5, IsOdd)).Matches(GivesError("type error: expected an integer, but was “4.5” of type “float64”"))
+		})
+	})
+
+	c.Specify("Matcher: IsMultipleOf", func() {
+		c.Expect(E(8, IsMultipleOf(4))).Matches(Succeeds)
+		c.Expect(E(0, IsMultipleOf(4))).Matches(Succeeds)
+		c.Expect(E(-8, IsMultipleOf(4))).Matches(Succeeds)
+		c.Expect(E(uint16(4096), IsMultipleOf(int64(512)))).Matches(Succeeds)
+		c.Expect(E(7.5, IsMultipleOf(2.5))).Matches(Succeeds)
+		c.Expect(E(7.5, IsMultipleOf(2))).Matches(Fails)
+		c.Expect(E(9, IsMultipleOf(4))).Matches(FailsWithMessage(
+			"is a multiple of “4”",
+			"is NOT a multiple of “4”"))
+
+		c.Specify("integers are divided without converting them to floats", func() {
+			c.Expect(E(int64(9007199254740993), IsMultipleOf(3))).Matches(Succeeds)
+			c.Expect(E(int64(9007199254740993), IsMultipleOf(2))).Matches(Fails)
+			c.Expect(E(int64(-9007199254740993), IsMultipleOf(int8(-3)))).Matches(Succeeds)
+			c.Expect(E(uint64(math.MaxUint64), IsMultipleOf(5))).Matches(Succeeds)
+			c.Expect(E(uint64(math.MaxUint64), IsMultipleOf(2))).Matches(Fails)
+			c.Expect(E(int64(math.MinInt64), IsMultipleOf(int64(math.MinInt64)))).Matches(Succeeds)
+		})
+		c.Specify("cannot divide by zero", func() {
+			c.Expect(E(9, IsMultipleOf(0))).Matches(GivesError("illegal argument: IsMultipleOf requires a non-zero divisor"))
+		})
+		c.Specify("cannot divide non-numeric values", func() {
+			c.Expect(E("9", IsMultipleOf(4))).Matches(GivesError("type error: expected a number, but was “9” of type “string”"))
+		})
+	})
+
+	c.Specify("Ordering matchers accept all numeric types", func() {
+		c.Expect(E(int8(-5), IsLessThan, uint64(3))).Matches(Succeeds)
+		c.Expect(E(int16(5), IsGreaterThan, int32(3))).Matches(Succeeds)
+		c.Expect(E(int64(5), IsGreaterThan, uint(3))).Matches(Succeeds)
+		c.Expect(E(uint8(5), IsGreaterThan, uint16(3))).Matches(Succeeds)
+		c.Expect(E(uint32(5), IsGreaterThan, float32(3.5))).Matches(Succeeds)
+		c.Expect(E(float64(3.5), IsLessThan, 4)).Matches(Succeeds)
+
+		c.Specify("but not other types", func() {
+			c.Expect(E("5", IsGreaterThan, 3)).Matches(GivesError("type error: expected a number, but was “5” of type “string”"))
+			c.Expect(E(5, IsGreaterThan, "3")).Matches(GivesError("type error: expected a number, but was “3” of type “string”"))
 		})
 	})
 
 	c.Specify("Matcher: Contains", func() {
 		values := []string{"one", "two", "three"}
 
-		c.Expect(E(values, Contains, "one")).Matches(Passes)
-		c.Expect(E(values, Contains, "two")).Matches(Passes)
-		c.Expect(E(values, Contains, "three")).Matches(Passes)
+		c.Expect(E(values, Contains, "one")).Matches(Succeeds)
+		c.Expect(E(values, Contains, "two")).Matches(Succeeds)
+		c.Expect(E(values, Contains, "three")).Matches(Succeeds)
 
 		c.Expect(E(values, Contains, "four")).Matches(FailsWithMessage(
 			"contains “four”",
@@ -176,10 +819,10 @@ func MatchersSpec(c nanospec.Context) {
 	c.Specify("Matcher: ContainsAll", func() {
 		values := []string{"one", "two", "three"}
 
-		c.Expect(E(values, ContainsAll, Values())).Matches(Passes)
-		c.Expect(E(values, ContainsAll, Values("one"))).Matches(Passes)
-		c.Expect(E(values, ContainsAll, Values("three", "two"))).Matches(Passes)
-		c.Expect(E(values, ContainsAll, Values("one", "two", "three"))).Matches(Passes)
+		c.Expect(E(values, ContainsAll, Values())).Matches(Succeeds)
+		c.Expect(E(values, ContainsAll, Values("one"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsAll, Values("three", "two"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsAll, Values("one", "two", "three"))).Matches(Succeeds)
 
 		c.Expect(E(values, ContainsAll, Values("four"))).Matches(Fails)
 		c.Expect(E(values, ContainsAll, Values("one", "four"))).Matches(FailsWithMessage(
@@ -190,10 +833,10 @@ func MatchersSpec(c nanospec.Context) {
 	c.Specify("Matcher: ContainsAny", func() {
 		values := []string{"one", "two", "three"}
 
-		c.Expect(E(values, ContainsAny, Values("one"))).Matches(Passes)
-		c.Expect(E(values, ContainsAny, Values("three", "two"))).Matches(Passes)
-		c.Expect(E(values, ContainsAny, Values("four", "one", "five"))).Matches(Passes)
-		c.Expect(E(values, ContainsAny, Values("one", "two", "three"))).Matches(Passes)
+		c.Expect(E(values, ContainsAny, Values("one"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsAny, Values("three", "two"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsAny, Values("four", "one", "five"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsAny, Values("one", "two", "three"))).Matches(Succeeds)
 
 		c.Expect(E(values, ContainsAny, Values())).Matches(Fails)
 		c.Expect(E(values, ContainsAny, Values("four"))).Matches(Fails)
@@ -202,59 +845,1062 @@ func MatchersSpec(c nanospec.Context) {
 			"does NOT contain any of “[four five]”"))
 	})
 
+	c.Specify("Matcher: ContainsOnly", func() {
+		values := Values("foo", "bar", "foo")
+
+		c.Expect(E(values, ContainsOnly(Values("foo", "bar")))).Matches(Succeeds)
+		c.Expect(E(values, ContainsOnly(Values("foo", "bar", "baz")))).Matches(Succeeds)
+		c.Expect(E(Values(1, 5, 2), ContainsOnly(Values(1, 2, 3)))).Matches(FailsWithMessage(
+			"contains only “[1 2 3]”, but “5” was unexpected",
+			"does NOT contain only “[1 2 3]”"))
+
+		c.Specify("matches empty collections", func() {
+			c.Expect(E(Values(), ContainsOnly(Values(1)))).Matches(Succeeds)
+		})
+		c.Specify("cannot match non-collections", func() {
+			c.Expect(E(Values(1), ContainsOnly(1))).Matches(GivesError(
+				"type error: expected a collection type, but was “1” of type “int”"))
+		})
+	})
+
 	c.Specify("Matcher: ContainsExactly", func() {
 		values := []string{"one", "two", "three"}
 
-		c.Expect(E(values, ContainsExactly, Values("one", "two", "three"))).Matches(Passes)
-		c.Expect(E(values, ContainsExactly, Values("three", "one", "two"))).Matches(Passes)
+		c.Expect(E(values, ContainsExactly, Values("one", "two", "three"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsExactly, Values("three", "one", "two"))).Matches(Succeeds)
 
 		c.Expect(E(values, ContainsExactly, Values())).Matches(Fails)
 		c.Expect(E(values, ContainsExactly, Values("four"))).Matches(Fails)
 		c.Expect(E(values, ContainsExactly, Values("one", "two"))).Matches(Fails)
 		c.Expect(E(values, ContainsExactly, Values("one", "two", "three", "four"))).Matches(FailsWithMessage(
-			"contains exactly “[one two three four]”",
+			"contains exactly “[one two three four]”, but “[four]” were missing",
 			"does NOT contain exactly “[one two three four]”"))
+		c.Expect(E(values, ContainsExactly, Values("one", "two"))).Matches(FailsWithMessage(
+			"contains exactly “[one two]”, but “[three]” were unexpected",
+			"does NOT contain exactly “[one two]”"))
+		c.Expect(E(values, ContainsExactly, Values("one", "four", "five"))).Matches(FailsWithMessage(
+			"contains exactly “[one four five]”, but “[four five]” were missing and “[two three]” were unexpected",
+			"does NOT contain exactly “[one four five]”"))
 
 		// duplicate values are allowed
 		values = []string{"a", "a", "b"}
 
-		c.Expect(E(values, ContainsExactly, Values("a", "a", "b"))).Matches(Passes)
-		c.Expect(E(values, ContainsExactly, Values("a", "b", "a"))).Matches(Passes)
+		c.Expect(E(values, ContainsExactly, Values("a", "a", "b"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsExactly, Values("a", "b", "a"))).Matches(Succeeds)
 
 		c.Expect(E(values, ContainsExactly, Values("a", "b", "b"))).Matches(Fails)
 		c.Expect(E(values, ContainsExactly, Values("a", "a", "a", "b"))).Matches(Fails)
-		c.Expect(E(values, ContainsExactly, Values("a", "a", "b", "b"))).Matches(Fails)
+		c.Expect(E(values, ContainsExactly, Values("a", "a", "b", "b"))).Matches(FailsWithMessage(
+			"contains exactly “[a a b b]”, but “[b]” were missing",
+			"does NOT contain exactly “[a a b b]”"))
 	})
 
 	c.Specify("Matcher: ContainsInOrder", func() {
 		values := []string{"one", "two", "three"}
 
-		c.Expect(E(values, ContainsInOrder, Values("one", "two", "three"))).Matches(Passes)
+		c.Expect(E(values, ContainsInOrder, Values("one", "two", "three"))).Matches(Succeeds)
 
 		c.Expect(E(values, ContainsInOrder, Values())).Matches(Fails)
-		c.Expect(E(values, ContainsInOrder, Values("one", "two"))).Matches(Fails)
-		c.Expect(E(values, ContainsInOrder, Values("one", "two", "four"))).Matches(Fails)
-		c.Expect(E(values, ContainsInOrder, Values("one", "two", "three", "four"))).Matches(Fails)
+		c.Expect(E(values, ContainsInOrder, Values("one", "two"))).Matches(FailsWithMessage(
+			"contains in order “[one two]”, but had 3 elements instead of 2",
+			"does NOT contain in order “[one two]”"))
+		c.Expect(E(values, ContainsInOrder, Values("one", "two", "four"))).Matches(FailsWithMessage(
+			"contains in order “[one two four]”, but at index 2 expected “four” but was “three”",
+			"does NOT contain in order “[one two four]”"))
+		c.Expect(E(values, ContainsInOrder, Values("one", "two", "three", "four"))).Matches(FailsWithMessage(
+			"contains in order “[one two three four]”, but had 3 elements instead of 4",
+			"does NOT contain in order “[one two three four]”"))
 		c.Expect(E(values, ContainsInOrder, Values("three", "one", "two"))).Matches(FailsWithMessage(
-			"contains in order “[three one two]”",
+			"contains in order “[three one two]”, but at index 0 expected “three” but was “one”",
 			"does NOT contain in order “[three one two]”"))
+		c.Expect(E(values, ContainsInOrder, Values("one", "three"))).Matches(FailsWithMessage(
+			"contains in order “[one three]”, but at index 1 expected “three” but was “two”",
+			"does NOT contain in order “[one three]”"))
 	})
 
 	c.Specify("Matcher: ContainsInPartialOrder", func() {
 		values := []string{"1", "2", "2", "3", "4"}
 
-		c.Expect(E(values, ContainsInPartialOrder, Values())).Matches(Passes)
-		c.Expect(E(values, ContainsInPartialOrder, Values("1"))).Matches(Passes)
-		c.Expect(E(values, ContainsInPartialOrder, Values("1", "2", "2"))).Matches(Passes)
-		c.Expect(E(values, ContainsInPartialOrder, Values("1", "2", "3"))).Matches(Passes)
-		c.Expect(E(values, ContainsInPartialOrder, Values("1", "2", "2", "3", "4"))).Matches(Passes)
-
-		c.Expect(E(values, ContainsInPartialOrder, Values("1", "1"))).Matches(Fails)
-		c.Expect(E(values, ContainsInPartialOrder, Values("2", "1"))).Matches(Fails)
-		c.Expect(E(values, ContainsInPartialOrder, Values("2", "2", "2"))).Matches(Fails)
+		c.Expect(E(values, ContainsInPartialOrder, Values())).Matches(Succeeds)
+		c.Expect(E(values, ContainsInPartialOrder, Values("1"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsInPartialOrder, Values("1", "2", "2"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsInPartialOrder, Values("1", "2", "3"))).Matches(Succeeds)
+		c.Expect(E(values, ContainsInPartialOrder, Values("1", "2", "2", "3", "4"))).Matches(Succeeds)
+
+		c.Expect(E(values, ContainsInPartialOrder, Values("1", "1"))).Matches(FailsWithMessage(
+			"contains in partial order “[1 1]”, but “1” was not found after index 0",
+			"does NOT contain in partial order “[1 1]”"))
+		c.Expect(E(values, ContainsInPartialOrder, Values("2", "1"))).Matches(FailsWithMessage(
+			"contains in partial order “[2 1]”, but “1” was not found after index 1",
+			"does NOT contain in partial order “[2 1]”"))
+		c.Expect(E(values, ContainsInPartialOrder, Values("2", "2", "2"))).Matches(FailsWithMessage(
+			"contains in partial order “[2 2 2]”, but “2” was not found after index 2",
+			"does NOT contain in partial order “[2 2 2]”"))
 		c.Expect(E(values, ContainsInPartialOrder, Values("1", "4", "3"))).Matches(FailsWithMessage(
-			"contains in partial order “[1 4 3]”",
+			"contains in partial order “[1 4 3]”, but “3” was not found after index 4",
 			"does NOT contain in partial order “[1 4 3]”"))
+		c.Expect(E(values, ContainsInPartialOrder, Values("5"))).Matches(FailsWithMessage(
+			"contains in partial order “[5]”, but “5” was not found",
+			"does NOT contain in partial order “[5]”"))
+	})
+
+	c.Specify("Matcher: ContainsSequence", func() {
+		values := Values(1, 2, 3, 4, 5)
+
+		c.Expect(E(values, ContainsSequence(Values(2, 3, 4)))).Matches(Succeeds)
+		c.Expect(E(values, ContainsSequence(Values(1, 2, 3, 4, 5)))).Matches(Succeeds)
+		c.Expect(E(values, ContainsSequence(Values(2, 4)))).Matches(FailsWithMessage(
+			"contains sequence “[2 4]”",
+			"does NOT contain sequence “[2 4]”"))
+		c.Expect(E(values, ContainsSequence(Values(4, 5, 6)))).Matches(Fails)
+
+		c.Specify("overlapping patterns", func() {
+			c.Expect(E(Values(1, 1, 2, 1, 1, 2, 3), ContainsSequence(Values(1, 1, 2, 3)))).Matches(Succeeds)
+			c.Expect(E(Values(1, 2, 1, 2, 1), ContainsSequence(Values(2, 1, 2, 1)))).Matches(Succeeds)
+		})
+		c.Specify("empty sequences are always contained", func() {
+			c.Expect(E(values, ContainsSequence(Values()))).Matches(Succeeds)
+			c.Expect(E(Values(), ContainsSequence(Values()))).Matches(Succeeds)
+		})
+		c.Specify("sequences longer than the collection are not contained", func() {
+			c.Expect(E(Values(1), ContainsSequence(Values(1, 1)))).Matches(Fails)
+		})
+		c.Specify("cannot match non-collections", func() {
+			c.Expect(E(values, ContainsSequence(1))).Matches(GivesError(
+				"type error: expected a collection type, but was “1” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: StartsWithSlice", func() {
+		values := Values(1, 2, 3)
+
+		c.Expect(E(values, StartsWithSlice(Values(1, 2)))).Matches(Succeeds)
+		c.Expect(E(values, StartsWithSlice(Values(1, 2, 3)))).Matches(Succeeds)
+		c.Expect(E(Values(3, 2, 1), StartsWithSlice(Values(1, 2)))).Matches(FailsWithMessage(
+			"starts with “[1 2]”, but started with “[3 2]”",
+			"does NOT start with “[1 2]”"))
+
+		c.Specify("empty prefixes always match", func() {
+			c.Expect(E(values, StartsWithSlice(Values()))).Matches(Succeeds)
+			c.Expect(E(Values(), StartsWithSlice(Values()))).Matches(Succeeds)
+		})
+		c.Specify("the collection must not be shorter than the prefix", func() {
+			c.Expect(E(Values(1), StartsWithSlice(Values(1, 2)))).Matches(FailsWithMessage(
+				"starts with “[1 2]”, but had fewer than 2 elements",
+				"does NOT start with “[1 2]”"))
+		})
+	})
+
+	c.Specify("Matcher: EndsWithSlice", func() {
+		values := Values(1, 2, 3)
+
+		c.Expect(E(values, EndsWithSlice(Values(2, 3)))).Matches(Succeeds)
+		c.Expect(E(values, EndsWithSlice(Values(1, 2, 3)))).Matches(Succeeds)
+		c.Expect(E(Values(3, 2, 1), EndsWithSlice(Values(2, 3)))).Matches(FailsWithMessage(
+			"ends with “[2 3]”, but ended with “[2 1]”",
+			"does NOT end with “[2 3]”"))
+
+		c.Specify("empty suffixes always match", func() {
+			c.Expect(E(values, EndsWithSlice(Values()))).Matches(Succeeds)
+			c.Expect(E(Values(), EndsWithSlice(Values()))).Matches(Succeeds)
+		})
+		c.Specify("the collection must not be shorter than the suffix", func() {
+			c.Expect(E(Values(3), EndsWithSlice(Values(2, 3)))).Matches(FailsWithMessage(
+				"ends with “[2 3]”, but had fewer than 2 elements",
+				"does NOT end with “[2 3]”"))
+		})
+	})
+
+	c.Specify("Matcher: IsSorted", func() {
+		c.Expect(E([]int{1, 2, 2, 3}, IsSorted)).Matches(Succeeds)
+		c.Expect(E([]float64{-1.5, 0, 2.5}, IsSorted)).Matches(Succeeds)
+		c.Expect(E([]string{"apple", "banana"}, IsSorted)).Matches(Succeeds)
+		c.Expect(E([]int{}, IsSorted)).Matches(Succeeds)
+		c.Expect(E([]int{1, 8, 5}, IsSorted)).Matches(FailsWithMessage(
+			"is sorted, but “5” at index 2 was less than “8” at index 1",
+			"is NOT sorted"))
+
+		c.Specify("cannot compare other types", func() {
+			c.Expect(E(Values(1, "a"), IsSorted)).Matches(GivesError(
+				"type error: expected numbers or strings, but was “a” of type “string” and “1” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: IsSortedBy", func() {
+		byLength := IsSortedBy(func(a, b interface{}) bool {
+			return len(a.(string)) < len(b.(string))
+		})
+
+		c.Expect(E([]string{"b", "aa", "ccc"}, byLength)).Matches(Succeeds)
+		c.Expect(E([]string{"b", "ccc", "aa"}, byLength)).Matches(FailsWithMessage(
+			"is sorted, but “aa” at index 2 was less than “ccc” at index 1",
+			"is NOT sorted"))
+	})
+
+	c.Specify("Matcher: IsMonotonicallyIncreasing", func() {
+		c.Expect(E(Values(1, 2, 3), IsMonotonicallyIncreasing)).Matches(Succeeds)
+		c.Expect(E(Values(1, 2, 2, 3), IsMonotonicallyIncreasing)).Matches(Succeeds)
+		c.Expect(E(Values(), IsMonotonicallyIncreasing)).Matches(Succeeds)
+		c.Expect(E(Values(1, 5, 3), IsMonotonicallyIncreasing)).Matches(FailsWithMessage(
+			"is monotonically increasing, but “3” at index 2 was less than “5” at index 1",
+			"is NOT monotonically increasing"))
+
+		c.Specify("mixed numeric types", func() {
+			c.Expect(E(Values(1, 1.5, uint8(2)), IsMonotonicallyIncreasing)).Matches(Succeeds)
+		})
+		c.Specify("cannot compare non-numbers", func() {
+			c.Expect(E(Values(1, "a"), IsMonotonicallyIncreasing)).Matches(GivesError(
+				"type error: expected a number, but was “a” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: IsMonotonicallyDecreasing", func() {
+		c.Expect(E(Values(3, 2, 1), IsMonotonicallyDecreasing)).Matches(Succeeds)
+		c.Expect(E(Values(3, 2, 2, 1), IsMonotonicallyDecreasing)).Matches(Succeeds)
+		c.Expect(E(Values(5, 1, 3), IsMonotonicallyDecreasing)).Matches(FailsWithMessage(
+			"is monotonically decreasing, but “3” at index 2 was greater than “1” at index 1",
+			"is NOT monotonically decreasing"))
+	})
+
+	c.Specify("Matcher: IsStrictlyIncreasing", func() {
+		c.Expect(E(Values(1, 2, 3), IsStrictlyIncreasing)).Matches(Succeeds)
+		c.Expect(E(Values(1, 2, 2, 3), IsStrictlyIncreasing)).Matches(FailsWithMessage(
+			"is strictly increasing, but “2” at index 2 was not greater than “2” at index 1",
+			"is NOT strictly increasing"))
+	})
+
+	c.Specify("Matcher: IsStrictlyDecreasing", func() {
+		c.Expect(E(Values(3, 2, 1), IsStrictlyDecreasing)).Matches(Succeeds)
+		c.Expect(E(Values(3, 2, 2, 1), IsStrictlyDecreasing)).Matches(FailsWithMessage(
+			"is strictly decreasing, but “2” at index 2 was not less than “2” at index 1",
+			"is NOT strictly decreasing"))
+	})
+
+	c.Specify("Matcher: IsSubsetOf", func() {
+		c.Expect(E(Values(1, 2), IsSubsetOf(Values(1, 2, 3)))).Matches(Succeeds)
+		c.Expect(E(Values(3, 1), IsSubsetOf(Values(1, 2, 3)))).Matches(Succeeds)
+		c.Expect(E(Values(), IsSubsetOf(Values(1, 2, 3)))).Matches(Succeeds)
+		c.Expect(E(Values(), IsSubsetOf(Values()))).Matches(Succeeds)
+		c.Expect(E(Values(1, 2), IsSubsetOf(Values(3, 4, 5)))).Matches(FailsWithMessage(
+			"is a subset of “[3 4 5]”, but “1” was not in it",
+			"is NOT a subset of “[3 4 5]”"))
+
+		c.Specify("cannot check non-collections", func() {
+			c.Expect(E(1, IsSubsetOf(Values(1)))).Matches(GivesError("type error: expected a collection type, but was “1” of type “int”"))
+			c.Expect(E(Values(1), IsSubsetOf(1))).Matches(GivesError("type error: expected a collection type, but was “1” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: IsSupersetOf", func() {
+		c.Expect(E(Values(1, 2, 3), IsSupersetOf(Values(1, 2)))).Matches(Succeeds)
+		c.Expect(E(Values(1, 2, 3), IsSupersetOf(Values(3, 1)))).Matches(Succeeds)
+		c.Expect(E(Values(1, 2, 3), IsSupersetOf(Values()))).Matches(Succeeds)
+		c.Expect(E(Values(1, 2, 3), IsSupersetOf(Values(1, 2, 4)))).Matches(FailsWithMessage(
+			"is a superset of “[1 2 4]”, but “4” was not in it",
+			"is NOT a superset of “[1 2 4]”"))
+
+		c.Specify("duplicate elements must occur as many times", func() {
+			c.Expect(E(Values(3, 1, 3), IsSupersetOf(Values(3, 3)))).Matches(Succeeds)
+			c.Expect(E(Values(3, 1, 2), IsSupersetOf(Values(3, 3)))).Matches(FailsWithMessage(
+				"is a superset of “[3 3]”, but “3” was not in it",
+				"is NOT a superset of “[3 3]”"))
+		})
+	})
+
+	c.Specify("Matcher: IsPermutationOf", func() {
+		c.Expect(E(Values(1, 2, 3), IsPermutationOf, Values(1, 2, 3))).Matches(Succeeds)
+		c.Expect(E(Values(3, 1, 2), IsPermutationOf, Values(1, 2, 3))).Matches(Succeeds)
+		c.Expect(E(Values(), IsPermutationOf, Values())).Matches(Succeeds)
+		c.Expect(E(Values(1, 2), IsPermutationOf, Values(1, 2, 3))).Matches(Fails)
+		c.Expect(E(Values(1, 2, 3, 4), IsPermutationOf, Values(1, 2, 3))).Matches(Fails)
+		c.Expect(E(Values(1, 2, 2), IsPermutationOf, Values(1, 2, 3))).Matches(FailsWithMessage(
+			"is a permutation of “[1 2 3]”",
+			"is NOT a permutation of “[1 2 3]”"))
+
+		c.Specify("every nil element counts separately", func() {
+			c.Expect(E(Values(nil, 1, nil), IsPermutationOf, Values(nil, nil, 1))).Matches(Succeeds)
+			c.Expect(E(Values(nil, 1, 1), IsPermutationOf, Values(nil, nil, 1))).Matches(Fails)
+		})
+	})
+
+	c.Specify("Matcher: IsDistinct", func() {
+		c.Expect(E(Values(1, 2, 3), IsDistinct)).Matches(Succeeds)
+		c.Expect(E(Values(), IsDistinct)).Matches(Succeeds)
+		c.Expect(E(Values(1, 3, 2, 3), IsDistinct)).Matches(FailsWithMessage(
+			"has distinct elements, but “3” occurred more than once",
+			"does NOT have distinct elements"))
+
+		c.Specify("elements are compared using the Equality interface", func() {
+			c.Expect(E(Values(DummyStruct{1, 1}, DummyStruct{1, 2}), IsDistinct)).Matches(Fails)
+		})
+		c.Specify("channels", func() {
+			values := make(chan int, 10)
+			values <- 1
+			values <- 1
+			close(values)
+			c.Expect(E(values, IsDistinct)).Matches(Fails)
+		})
+	})
+
+	c.Specify("Matcher: AllElements", func() {
+		c.Expect(E(Values(1, 2, 3), AllElements(IsPositive))).Matches(Succeeds)
+		c.Expect(E(Values(1, 2, -1, -2), AllElements(IsPositive))).Matches(FailsWithMessage(
+			"has all elements matching, but element at index 2 (“-1”) failed: is positive",
+			"does NOT have all elements matching"))
+
+		c.Specify("matches empty collections", func() {
+			c.Expect(E(Values(), AllElements(IsPositive))).Matches(Succeeds)
+		})
+		c.Specify("combined with Not", func() {
+			c.Expect(E(Values(1, "a"), AllElements(Not(IsNil)))).Matches(Succeeds)
+			c.Expect(E(Values(1, nil), AllElements(Not(IsNil)))).Matches(Fails)
+		})
+		c.Specify("gives the expected value to the inner matcher", func() {
+			c.Expect(E(Values(5, 5), AllElements(Equals), 5)).Matches(Succeeds)
+			c.Expect(E(Values(5, 6), AllElements(Equals), 5)).Matches(FailsWithMessage(
+				"has all elements matching, but element at index 1 (“6”) failed: equals “5”",
+				"does NOT have all elements matching"))
+		})
+		c.Specify("custom matchers", func() {
+			isShort := func(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+				match = len(actual.(string)) < 4
+				pos = Messagef(actual, "is short")
+				neg = Messagef(actual, "is NOT short")
+				return
+			}
+			c.Expect(E(Values("a", "abc"), AllElements(isShort))).Matches(Succeeds)
+			c.Expect(E(Values("a", "hello"), AllElements(isShort))).Matches(FailsWithMessage(
+				"has all elements matching, but element at index 1 (“hello”) failed: is short",
+				"does NOT have all elements matching"))
+		})
+		c.Specify("reports errors of the inner matcher", func() {
+			c.Expect(E(Values(1, "a"), AllElements(IsPositive))).Matches(GivesError(
+				"type error: expected a number, but was “a” of type “string”"))
+		})
+		c.Specify("cannot match non-collections", func() {
+			c.Expect(E(1, AllElements(IsPositive))).Matches(GivesError(
+				"type error: expected a collection type, but was “1” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: AnyElement", func() {
+		c.Expect(E(Values(-1, 2, -3), AnyElement(IsPositive))).Matches(Succeeds)
+		c.Expect(E(Values(-1, 2, -3), Not(AnyElement(IsPositive)))).Matches(FailsWithMessage(
+			"does NOT have an element matching, but element at index 1 (“2”) matched",
+			"has an element matching, but none did (checked 3 elements)"))
+		c.Expect(E(Values(-1, -2, -3, -4, -5), AnyElement(IsPositive))).Matches(FailsWithMessage(
+			"has an element matching, but none did (checked 5 elements)",
+			"does NOT have an element matching"))
+
+		c.Specify("never matches empty collections", func() {
+			c.Expect(E(Values(), AnyElement(IsPositive))).Matches(FailsWithMessage(
+				"has an element matching, but none did (checked 0 elements)",
+				"does NOT have an element matching"))
+		})
+		c.Specify("single-element collections", func() {
+			c.Expect(E(Values(1), AnyElement(IsPositive))).Matches(Succeeds)
+			c.Expect(E(Values(-1), AnyElement(IsPositive))).Matches(Fails)
+		})
+		c.Specify("the matching element may be the last one", func() {
+			values := make([]int, 1000)
+			values[999] = 42
+			c.Expect(E(values, AnyElement(Equals), 42)).Matches(Succeeds)
+		})
+		c.Specify("is equivalent to negating AllElements", func() {
+			c.Expect(E(Values(nil, 1), AnyElement(Not(IsNil)))).Matches(Succeeds)
+			c.Expect(E(Values(nil, 1), Not(AllElements(IsNil)))).Matches(Succeeds)
+			c.Expect(E(Values(nil, nil), AnyElement(Not(IsNil)))).Matches(Fails)
+			c.Expect(E(Values(nil, nil), Not(AllElements(IsNil)))).Matches(Fails)
+		})
+		c.Specify("reports errors of the inner matcher", func() {
+			c.Expect(E(Values(-1, "a"), AnyElement(IsPositive))).Matches(GivesError(
+				"type error: expected a number, but was “a” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: NoneElement", func() {
+		c.Expect(E(Values(-1, -2), NoneElement(IsPositive))).Matches(Succeeds)
+		c.Expect(E(Values(1, 0, 2), NoneElement(IsZero))).Matches(FailsWithMessage(
+			"has no element matching, but element at index 1 (“0”) matched",
+			"has an element matching, but none did (checked 3 elements)"))
+
+		c.Specify("matches empty collections", func() {
+			c.Expect(E(Values(), NoneElement(IsPositive))).Matches(Succeeds)
+		})
+		c.Specify("is equivalent to negating AnyElement", func() {
+			c.Expect(E(Values(-1, 1), NoneElement(IsPositive))).Matches(Fails)
+			c.Expect(E(Values(-1, 1), Not(AnyElement(IsPositive)))).Matches(Fails)
+		})
+		c.Specify("reports errors of the inner matcher", func() {
+			c.Expect(E(Values(-1, "a"), NoneElement(IsPositive))).Matches(GivesError(
+				"type error: expected a number, but was “a” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: HasLength", func() {
+		c.Expect(E([]string{"one", "two", "three"}, HasLength, 3)).Matches(Succeeds)
+		c.Expect(E([...]int{1, 2}, HasLength, 2)).Matches(Succeeds)
+		c.Expect(E("abcd", HasLength, 4)).Matches(Succeeds)
+		c.Expect(E(map[string]int{"a": 1}, HasLength, 1)).Matches(Succeeds)
+
+		c.Expect(E([]string{"one", "two", "three"}, HasLength, 5)).Matches(FailsWithMessage(
+			"has length “5”",
+			"does NOT have length “5”"))
+
+		c.Specify("buffered channels", func() {
+			values := make(chan int, 10)
+			values <- 1
+			values <- 2
+			c.Expect(E(values, HasLength, 2)).Matches(Succeeds)
+		})
+		c.Specify("lists", func() {
+			values := list.New()
+			values.PushBack(1)
+			c.Expect(E(values, HasLength, 1)).Matches(Succeeds)
+		})
+		c.Specify("cannot measure types without a length", func() {
+			c.Expect(E(42, HasLength, 1)).Matches(GivesError("type error: expected a type with a length, but was “42” of type “int”"))
+		})
+		c.Specify("the expected length may be of any integer type", func() {
+			c.Expect(E([]int{1, 2, 3}, HasLength, int64(3))).Matches(Succeeds)
+			c.Expect(E([]int{1, 2, 3}, HasLength, uint(3))).Matches(Succeeds)
+			c.Expect(E([]int{1, 2, 3}, HasLength, uint8(4))).Matches(Fails)
+		})
+		c.Specify("the expected length must be an integer", func() {
+			c.Expect(E([]int{1, 2, 3}, HasLength, 3.0)).Matches(GivesError("type error: expected an integer length, but was “3” of type “float64”"))
+			c.Expect(E([]int{1, 2, 3}, HasLength, "3")).Matches(GivesError("type error: expected an integer length, but was “3” of type “string”"))
+		})
+	})
+
+	c.Specify("Matcher: IsEmpty", func() {
+		c.Expect(E([]string{}, IsEmpty)).Matches(Succeeds)
+		c.Expect(E("", IsEmpty)).Matches(Succeeds)
+		c.Expect(E(map[string]int{}, IsEmpty)).Matches(Succeeds)
+		c.Expect(E(make(chan int, 10), IsEmpty)).Matches(Succeeds)
+
+		c.Expect(E([]string{"one"}, IsEmpty)).Matches(FailsWithMessage(
+			"is empty",
+			"is NOT empty"))
+
+		c.Specify("cannot measure types without a length", func() {
+			c.Expect(E(42, IsEmpty)).Matches(GivesError("type error: expected a type with a length, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: HasAtLeastNElements", func() {
+		c.Expect(E([]int{1, 2, 3}, HasAtLeastNElements(3))).Matches(Succeeds)
+		c.Expect(E([...]int{1, 2, 3, 4}, HasAtLeastNElements(3))).Matches(Succeeds)
+		c.Expect(E("abc", HasAtLeastNElements(2))).Matches(Succeeds)
+		c.Expect(E(map[string]int{"a": 1}, HasAtLeastNElements(1))).Matches(Succeeds)
+		c.Expect(E([]int{1}, HasAtLeastNElements(3))).Matches(FailsWithMessage(
+			"has at least 3 elements, but had 1",
+			"does NOT have at least 3 elements, but had 1"))
+
+		c.Specify("zero elements are always enough", func() {
+			c.Expect(E([]int{}, HasAtLeastNElements(0))).Matches(Succeeds)
+		})
+		c.Specify("channels", func() {
+			values := make(chan int, 10)
+			values <- 1
+			c.Expect(E(values, HasAtLeastNElements(1))).Matches(Succeeds)
+			c.Expect(E(values, HasAtLeastNElements(2))).Matches(Fails)
+		})
+		c.Specify("cannot measure types without a length", func() {
+			c.Expect(E(42, HasAtLeastNElements(1))).Matches(GivesError("type error: expected a type with a length, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: HasAtMostNElements", func() {
+		c.Expect(E([]int{1, 2, 3}, HasAtMostNElements(3))).Matches(Succeeds)
+		c.Expect(E([...]int{1}, HasAtMostNElements(3))).Matches(Succeeds)
+		c.Expect(E("abc", HasAtMostNElements(2))).Matches(Fails)
+		c.Expect(E(map[string]int{}, HasAtMostNElements(0))).Matches(Succeeds)
+		c.Expect(E([]int{1, 2, 3, 4, 5, 6, 7}, HasAtMostNElements(5))).Matches(FailsWithMessage(
+			"has at most 5 elements, but had 7",
+			"does NOT have at most 5 elements, but had 7"))
+
+		c.Specify("channels", func() {
+			values := make(chan int, 10)
+			values <- 1
+			values <- 2
+			c.Expect(E(values, HasAtMostNElements(2))).Matches(Succeeds)
+			c.Expect(E(values, HasAtMostNElements(1))).Matches(Fails)
+		})
+		c.Specify("cannot measure types without a length", func() {
+			c.Expect(E(42, HasAtMostNElements(1))).Matches(GivesError("type error: expected a type with a length, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: HasExactlyOneElement", func() {
+		c.Expect(E(Values(1), HasExactlyOneElement)).Matches(Succeeds)
+		c.Expect(E(Values(), HasExactlyOneElement)).Matches(FailsWithMessage(
+			"has exactly one element, but had 0",
+			"does NOT have exactly one element"))
+		c.Expect(E(Values(1, 2), HasExactlyOneElement)).Matches(FailsWithMessage(
+			"has exactly one element, but had 2",
+			"does NOT have exactly one element"))
+
+		c.Specify("maps", func() {
+			c.Expect(E(map[string]int{"a": 1}, HasExactlyOneElement)).Matches(Succeeds)
+		})
+		c.Specify("lists", func() {
+			values := list.New()
+			values.PushBack(1)
+			c.Expect(E(values, HasExactlyOneElement)).Matches(Succeeds)
+			values.PushBack(2)
+			c.Expect(E(values, HasExactlyOneElement)).Matches(Fails)
+		})
+		c.Specify("channels are not drained", func() {
+			values := make(chan int, 2)
+			values <- 1
+			c.Expect(E(values, HasExactlyOneElement)).Matches(Succeeds)
+			c.Expect(len(values)).Equals(1)
+		})
+		c.Specify("the message shows the original collection", func() {
+			values := []int{1, 2}
+			ex := E(values, HasExactlyOneElement)
+			c.Expect(ex.pos.Actual()).Equals(values)
+		})
+		c.Specify("cannot match values without a length", func() {
+			c.Expect(E(1, HasExactlyOneElement)).Matches(GivesError(
+				"type error: expected a type with a length, but was “1” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: HasExactlyOneElementMatching", func() {
+		c.Expect(E(Values(5), HasExactlyOneElementMatching(IsPositive))).Matches(Succeeds)
+		c.Expect(E(Values(-5), HasExactlyOneElementMatching(IsPositive))).Matches(FailsWithMessage(
+			"has exactly one element which is positive",
+			"does NOT have exactly one element which is positive"))
+		c.Expect(E(Values(), HasExactlyOneElementMatching(IsPositive))).Matches(FailsWithMessage(
+			"has exactly one element, but had 0",
+			"does NOT have exactly one element"))
+		c.Expect(E(Values(5, 6), HasExactlyOneElementMatching(IsPositive))).Matches(FailsWithMessage(
+			"has exactly one element, but had 2",
+			"does NOT have exactly one element"))
+
+		c.Specify("gives the expected value to the inner matcher", func() {
+			c.Expect(E(Values("x"), HasExactlyOneElementMatching(Equals), "x")).Matches(Succeeds)
+		})
+	})
+
+	c.Specify("Matcher: HasPrefix", func() {
+		c.Expect(E("Hello world", HasPrefix("Hello"))).Matches(Succeeds)
+		c.Expect(E("Hello world", HasPrefix(""))).Matches(Succeeds)
+		c.Expect(E("World example", HasPrefix("Hello"))).Matches(FailsWithMessage(
+			"has prefix “Hello”",
+			"does NOT have prefix “Hello”"))
+
+		c.Specify("cannot check non-strings", func() {
+			c.Expect(E(42, HasPrefix("4"))).Matches(GivesError("type error: expected a string, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: HasSuffix", func() {
+		c.Expect(E("Hello world", HasSuffix("world"))).Matches(Succeeds)
+		c.Expect(E("Hello world", HasSuffix(""))).Matches(Succeeds)
+		c.Expect(E("World example", HasSuffix("world"))).Matches(FailsWithMessage(
+			"has suffix “world”",
+			"does NOT have suffix “world”"))
+
+		c.Specify("cannot check non-strings", func() {
+			c.Expect(E(42, HasSuffix("2"))).Matches(GivesError("type error: expected a string, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: ContainsSubstring", func() {
+		c.Expect(E("foo bar", ContainsSubstring("foo"))).Matches(Succeeds)
+		c.Expect(E("foo bar", ContainsSubstring("o b"))).Matches(Succeeds)
+		c.Expect(E("foo bar", ContainsSubstring(""))).Matches(Succeeds)
+		c.Expect(E("bar baz", ContainsSubstring("foo"))).Matches(FailsWithMessage(
+			"contains substring “foo”",
+			"does NOT contain substring “foo”"))
+
+		c.Specify("cannot check non-strings", func() {
+			c.Expect(E([]string{"foo"}, ContainsSubstring("foo"))).Matches(GivesError("type error: expected a string, but was “[foo]” of type “[]string”"))
+		})
+	})
+
+	c.Specify("Matcher: MatchesRegexp", func() {
+		c.Expect(E("foo123", MatchesRegexp(`^foo\d+$`))).Matches(Succeeds)
+		c.Expect(E("a foo b", MatchesRegexp("foo"))).Matches(Succeeds)
+		c.Expect(E("bar123", MatchesRegexp(`^foo\d+$`))).Matches(FailsWithMessage(
+			`matches regexp “^foo\d+$”`,
+			`does NOT match regexp “^foo\d+$”`))
+
+		c.Specify("invalid patterns are reported as errors", func() {
+			c.Expect(E("foo", MatchesRegexp("foo("))).Matches(GivesError(
+				"illegal argument: invalid regexp “foo(”: error parsing regexp: missing closing ): `foo(`"))
+		})
+		c.Specify("cannot match non-strings", func() {
+			c.Expect(E(123, MatchesRegexp(`\d+`))).Matches(GivesError("type error: expected a string, but was “123” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: MatchesCompiledRegexp", func() {
+		re := regexp.MustCompile(`^foo\d+$`)
+
+		c.Expect(E("foo123", MatchesCompiledRegexp(re))).Matches(Succeeds)
+		c.Expect(E("bar123", MatchesCompiledRegexp(re))).Matches(FailsWithMessage(
+			`matches regexp “^foo\d+$”`,
+			`does NOT match regexp “^foo\d+$”`))
+		c.Expect(E(123, MatchesCompiledRegexp(re))).Matches(GivesError("type error: expected a string, but was “123” of type “int”"))
+	})
+
+	c.Specify("Matcher: MatchesGlob", func() {
+		c.Expect(E("main.go", MatchesGlob("*.go"))).Matches(Succeeds)
+		c.Expect(E("config_dev.yaml", MatchesGlob("config_*.yaml"))).Matches(Succeeds)
+		c.Expect(E("file1.txt", MatchesGlob("file?.txt"))).Matches(Succeeds)
+		c.Expect(E("file10.txt", MatchesGlob("file?.txt"))).Matches(Fails)
+		c.Expect(E("b.go", MatchesGlob("[a-c].go"))).Matches(Succeeds)
+		c.Expect(E("d.go", MatchesGlob("[a-c].go"))).Matches(Fails)
+		c.Expect(E("main.py", MatchesGlob("*.go"))).Matches(FailsWithMessage(
+			"matches glob “*.go”",
+			"does NOT match glob “*.go”"))
+
+		c.Specify("invalid patterns are reported as errors", func() {
+			c.Expect(E("a.go", MatchesGlob("[a-"))).Matches(GivesError("illegal argument: invalid glob “[a-”: syntax error in pattern"))
+		})
+		c.Specify("cannot match non-strings", func() {
+			c.Expect(E(42, MatchesGlob("*"))).Matches(GivesError("type error: expected a string, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: EqualsFold", func() {
+		c.Expect(E("hello", EqualsFold, "hello")).Matches(Succeeds)
+		c.Expect(E("HELLO", EqualsFold, "hello")).Matches(Succeeds)
+		c.Expect(E("Straße", EqualsFold, "STRAßE")).Matches(Succeeds)
+		c.Expect(E("WORLD", EqualsFold, "hello")).Matches(FailsWithMessage(
+			"equals “hello” (case-insensitive)",
+			"does NOT equal “hello” (case-insensitive)"))
+
+		c.Specify("cannot compare non-strings", func() {
+			c.Expect(E(42, EqualsFold, "42")).Matches(GivesError("type error: expected a string, but was “42” of type “int”"))
+			c.Expect(E("42", EqualsFold, 42)).Matches(GivesError("type error: expected a string, but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: Panics", func() {
+		c.Expect(E(func() { panic("boom") }, Panics)).Matches(Succeeds)
+		c.Expect(E(func() { panic(errors.New("boom")) }, Panics)).Matches(Succeeds)
+		c.Expect(E(func() {}, Panics)).Matches(FailsWithMessage(
+			"panics",
+			"does NOT panic"))
+
+		c.Specify("reports the panic as the actual value", func() {
+			_, pos, _, _ := Panics(func() {}, nil)
+			c.Expect(pos.Actual()).Equals("<no panic>")
+			_, _, neg, _ := Panics(func() { panic("boom") }, nil)
+			c.Expect(neg.Actual()).Equals("panic: boom")
+		})
+		c.Specify("cannot call other than func()", func() {
+			c.Expect(E(42, Panics)).Matches(GivesError("type error: expected a func(), but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: PanicsWith", func() {
+		c.Expect(E(func() { panic("boom") }, PanicsWith("boom"))).Matches(Succeeds)
+		c.Expect(E(func() { panic(DummyStruct{42, 1}) }, PanicsWith(DummyStruct{42, 2}))).Matches(Succeeds)
+		c.Expect(E(func() {}, PanicsWith("boom"))).Matches(Fails)
+		c.Expect(E(func() { panic("bang") }, PanicsWith("boom"))).Matches(FailsWithMessage(
+			"panics with “boom”",
+			"does NOT panic with “boom”"))
+
+		c.Specify("cannot call other than func()", func() {
+			c.Expect(E(42, PanicsWith("boom"))).Matches(GivesError("type error: expected a func(), but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: PanicsWithMessage", func() {
+		c.Expect(E(func() { panic("integer overflow") }, PanicsWithMessage("overflow"))).Matches(Succeeds)
+		c.Expect(E(func() { panic(errors.New("integer overflow")) }, PanicsWithMessage("overflow"))).Matches(Succeeds)
+		c.Expect(E(func() {
+			var values []int
+			_ = values[5]
+		}, PanicsWithMessage("index out of range"))).Matches(Succeeds)
+		c.Expect(E(func() {}, PanicsWithMessage("overflow"))).Matches(Fails)
+		c.Expect(E(func() { panic("index out of bounds") }, PanicsWithMessage("overflow"))).Matches(FailsWithMessage(
+			"panics with message containing “overflow”",
+			"does NOT panic with message containing “overflow”"))
+
+		c.Specify("cannot call other than func()", func() {
+			c.Expect(E(42, PanicsWithMessage("overflow"))).Matches(GivesError("type error: expected a func(), but was “42” of type “int”"))
+		})
+	})
+
+	c.Specify("Matcher: PanicsMatching", func() {
+		c.Expect(E(func() { panic("index out of bounds") }, PanicsMatching(ContainsSubstring("index")))).Matches(Succeeds)
+		c.Expect(E(func() { panic(42) }, PanicsMatching(Equals), 42)).Matches(Succeeds)
+		c.Expect(E(func() { panic(-1) }, PanicsMatching(IsPositive))).Matches(FailsWithMessage(
+			"panics with a value which is positive",
+			"panics with a value which is NOT positive"))
+		c.Expect(E(func() {}, PanicsMatching(IsPositive))).Matches(FailsWithMessage(
+			"panics",
+			"does NOT panic"))
+
+		c.Specify("reports errors of the inner matcher", func() {
+			c.Expect(E(func() { panic("x") }, PanicsMatching(IsPositive))).Matches(GivesError(
+				"type error: expected a number, but was “x” of type “string”"))
+		})
+	})
+
+	c.Specify("CapturePanic", func() {
+		var cause interface{}
+
+		c.Specify("returns false when there is no panic", func() {
+			c.Expect(CapturePanic(func() {}, &cause)).IsFalse()
+			c.Expect(cause).Equals(nil)
+		})
+		c.Specify("stores the panic value", func() {
+			c.Expect(CapturePanic(func() { panic("boom") }, &cause)).IsTrue()
+			c.Expect(cause).Equals("boom")
+		})
+		c.Specify("stores errors used as panic values", func() {
+			err := errors.New("boom")
+			c.Expect(CapturePanic(func() { panic(err) }, &cause)).IsTrue()
+			c.Expect(cause).Equals(err)
+		})
+		c.Specify("detects nil panics", func() {
+			// The cause is a *runtime.PanicNilError since Go 1.21,
+			// but nil with older Go versions and GODEBUG=panicnil=1
+			c.Expect(CapturePanic(func() { panic(nil) }, &cause)).IsTrue()
+		})
+	})
+
+	c.Specify("Matcher: Eventually", func() {
+		c.Specify("matches when the condition becomes true before the timeout", func() {
+			calls := 0
+			condition := func() bool {
+				calls++
+				return calls == 3
+			}
+			c.Expect(E(nil, Eventually(condition, time.Second, time.Millisecond))).Matches(Succeeds)
+			c.Expect(calls).Equals(3)
+		})
+		c.Specify("does not wait when the condition is true immediately", func() {
+			start := time.Now()
+			c.Expect(E(nil, Eventually(func() bool { return true }, time.Second, time.Second))).Matches(Succeeds)
+			c.Expect(time.Since(start) < time.Second).IsTrue()
+		})
+		c.Specify("fails when the condition stays false until the timeout", func() {
+			c.Expect(E(nil, Eventually(func() bool { return false }, 20*time.Millisecond, time.Millisecond))).Matches(FailsWithMessage(
+				"becomes true within 20ms",
+				"does NOT become true within 20ms"))
+		})
+		c.Specify("sees changes made by other goroutines", func() {
+			done := make(chan bool, 1)
+			go func() { done <- true }()
+			isDone := func() bool {
+				select {
+				case <-done:
+					return true
+				default:
+					return false
+				}
+			}
+			c.Expect(E(nil, Eventually(isDone, time.Second, time.Millisecond))).Matches(Succeeds)
+		})
+	})
+
+	c.Specify("Matcher: Consistently", func() {
+		c.Specify("matches when the condition stays true for the whole duration", func() {
+			calls := 0
+			condition := func() bool {
+				calls++
+				return true
+			}
+			c.Expect(E(nil, Consistently(condition, 20*time.Millisecond, time.Millisecond))).Matches(Succeeds)
+			c.Expect(calls > 1).IsTrue()
+		})
+		c.Specify("fails as soon as the condition is false", func() {
+			start := time.Now()
+			c.Expect(E(nil, Consistently(func() bool { return false }, time.Second, time.Second))).Matches(FailsWithMessage(
+				"remains true for 1s, but was false after 0s",
+				"does NOT remain true for 1s"))
+			c.Expect(time.Since(start) < time.Second).IsTrue()
+		})
+	})
+
+	c.Specify("Polling matchers with a context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c.Specify("Eventually stops when the context is cancelled", func() {
+			c.Expect(E(nil, EventuallyContext(ctx, func() bool { return false }, time.Minute, time.Minute))).Matches(GivesError(
+				"polling was cancelled: context canceled"))
+		})
+		c.Specify("Consistently stops when the context is cancelled", func() {
+			c.Expect(E(nil, ConsistentlyContext(ctx, func() bool { return true }, time.Minute, time.Minute))).Matches(GivesError(
+				"polling was cancelled: context canceled"))
+		})
+		c.Specify("the condition is checked before the context", func() {
+			c.Expect(E(nil, EventuallyContext(ctx, func() bool { return true }, time.Minute, time.Minute))).Matches(Succeeds)
+		})
+	})
+
+	c.Specify("Matcher: IsError", func() {
+		wrapped := fmt.Errorf("reading config: %w", io.EOF)
+
+		c.Expect(E(io.EOF, IsError(io.EOF))).Matches(Succeeds)
+		c.Expect(E(wrapped, IsError(io.EOF))).Matches(Succeeds)
+		c.Expect(E(io.ErrUnexpectedEOF, IsError(io.EOF))).Matches(FailsWithMessage(
+			"has “EOF” in its error chain",
+			"does NOT have “EOF” in its error chain"))
+
+		c.Specify("cannot check non-errors", func() {
+			c.Expect(E("EOF", IsError(io.EOF))).Matches(GivesError("type error: expected an error, but was “EOF” of type “string”"))
+			c.Expect(E(nil, IsError(io.EOF))).Matches(GivesError("type error: expected an error, but was “<nil>” of type “<nil>”"))
+		})
+	})
+
+	c.Specify("Matcher: HasErrorMessage", func() {
+		c.Expect(E(errors.New("dial tcp: connection refused"), HasErrorMessage("connection refused"))).Matches(Succeeds)
+		c.Expect(E(errors.New("dial tcp: i/o timeout"), HasErrorMessage("connection refused"))).Matches(FailsWithMessage(
+			"has error message containing “connection refused”",
+			"does NOT have error message containing “connection refused”"))
+
+		c.Specify("cannot check non-errors", func() {
+			c.Expect(E("connection refused", HasErrorMessage("refused"))).Matches(GivesError("type error: expected an error, but was “connection refused” of type “string”"))
+			c.Expect(E(nil, HasErrorMessage("refused"))).Matches(GivesError("type error: expected an error, but was “<nil>” of type “<nil>”"))
+		})
+	})
+
+	c.Specify("Matcher: IsErrorType", func() {
+		wrapped := fmt.Errorf("request failed: %w", &DummyError{404})
+
+		c.Specify("matches errors of the target's type", func() {
+			var target *DummyError
+			c.Expect(E(wrapped, IsErrorType(&target))).Matches(Succeeds)
+
+			c.Specify("and assigns the matching error to the target", func() {
+				c.Expect(target.code).Equals(404)
+			})
+		})
+		c.Specify("matches errors which implement the target interface", func() {
+			var target interface{ Code() int }
+			c.Expect(E(wrapped, IsErrorType(&target))).Matches(Succeeds)
+			c.Expect(target.Code()).Equals(404)
+		})
+		c.Specify("fails for errors of other types", func() {
+			var target *DummyError
+			c.Expect(E(errors.New("request failed"), IsErrorType(&target))).Matches(FailsWithMessage(
+				"has an error of type “*gospec.DummyError” in its error chain",
+				"does NOT have an error of type “*gospec.DummyError” in its error chain"))
+		})
+		c.Specify("cannot check non-errors", func() {
+			var target *DummyError
+			c.Expect(E("request failed", IsErrorType(&target))).Matches(GivesError("type error: expected an error, but was “request failed” of type “string”"))
+		})
+		c.Specify("the target must be a pointer to an error type", func() {
+			var target DummyStruct
+			c.Expect(E(wrapped, IsErrorType(&target))).Matches(GivesError(
+				"type error: expected a pointer to an error type, but was “DummyStruct0” of type “*gospec.DummyStruct”"))
+			c.Expect(E(wrapped, IsErrorType(nil))).Matches(GivesError(
+				"type error: expected a pointer to an error type, but was “<nil>” of type “<nil>”"))
+		})
+	})
+
+	c.Specify("Matcher: IsOfType", func() {
+		c.Expect(E(42, IsOfType, 0)).Matches(Succeeds)
+		c.Expect(E(&DummyStruct{}, IsOfType, &DummyStruct{})).Matches(Succeeds)
+		c.Expect(E(DummyStruct{}, IsOfType, &DummyStruct{})).Matches(FailsWithMessage(
+			"is of type “*gospec.DummyStruct”",
+			"is NOT of type “*gospec.DummyStruct”"))
+
+		c.Specify("interface values are compared by their dynamic type", func() {
+			var err error = &DummyError{}
+			c.Expect(E(err, IsOfType, &DummyError{})).Matches(Succeeds)
+		})
+		c.Specify("reports the actual type", func() {
+			_, pos, _, _ := IsOfType("foo", 42)
+			c.Expect(fmt.Sprint(pos.Actual())).Equals("string")
+		})
+		c.Specify("cannot check untyped nil", func() {
+			c.Expect(E(nil, IsOfType, 42)).Matches(GivesError("type error: expected a typed value, but was “<nil>”"))
+			c.Expect(E(42, IsOfType, nil)).Matches(GivesError("type error: expected a typed value, but was “<nil>”"))
+		})
+	})
+
+	c.Specify("Matcher: IsOfTypeNamed", func() {
+		c.Expect(E(42, IsOfTypeNamed("int"))).Matches(Succeeds)
+		c.Expect(E(&DummyStruct{}, IsOfTypeNamed("*gospec.DummyStruct"))).Matches(Succeeds)
+		c.Expect(E(DummyStruct{}, IsOfTypeNamed("*gospec.DummyStruct"))).Matches(FailsWithMessage(
+			"is of type “*gospec.DummyStruct”",
+			"is NOT of type “*gospec.DummyStruct”"))
+		c.Expect(E(nil, IsOfTypeNamed("int"))).Matches(GivesError("type error: expected a typed value, but was “<nil>”"))
+	})
+
+	c.Specify("Matcher: Implements", func() {
+		c.Expect(E(&DummyError{}, Implements, (*error)(nil))).Matches(Succeeds)
+		c.Expect(E(DummyStruct{}, Implements, (*fmt.Stringer)(nil))).Matches(Succeeds)
+		c.Expect(E(&DummyStruct{}, Implements, (*fmt.Stringer)(nil))).Matches(Succeeds)
+		c.Expect(E(DummyError{}, Implements, (*error)(nil))).Matches(FailsWithMessage(
+			"implements “error”",
+			"does NOT implement “error”"))
+		c.Expect(E(42, Implements, (*io.Writer)(nil))).Matches(FailsWithMessage(
+			"implements “io.Writer”",
+			"does NOT implement “io.Writer”"))
+
+		c.Specify("the expected value must be a pointer to an interface", func() {
+			c.Expect(E(42, Implements, 42)).Matches(GivesError("type error: expected a pointer to an interface type, but was “42” of type “int”"))
+			c.Expect(E(42, Implements, nil)).Matches(GivesError("type error: expected a pointer to an interface type, but was “<nil>” of type “<nil>”"))
+			c.Expect(E(42, Implements, (*DummyError)(nil))).Matches(GivesError("type error: expected a pointer to an interface type, but was “<nil>” of type “*gospec.DummyError”"))
+		})
+		c.Specify("cannot check untyped nil", func() {
+			c.Expect(E(nil, Implements, (*error)(nil))).Matches(GivesError("type error: expected a typed value, but was “<nil>”"))
+		})
+	})
+
+	c.Specify("Matcher: HasKey", func() {
+		values := map[string]int{"user_id": 1, "name": 2}
+
+		c.Expect(E(values, HasKey, "user_id")).Matches(Succeeds)
+		c.Expect(E(values, HasKey, "name")).Matches(Succeeds)
+		c.Expect(E(values, HasKey, "email")).Matches(FailsWithMessage(
+			"has key “email”",
+			"does NOT have key “email”"))
+
+		c.Specify("keys are compared using the Equality interface", func() {
+			values := map[DummyStruct]int{DummyStruct{42, 1}: 1}
+			c.Expect(E(values, HasKey, DummyStruct{42, 2})).Matches(Succeeds)
+			c.Expect(E(values, HasKey, DummyStruct{999, 1})).Matches(Fails)
+		})
+		c.Specify("cannot check non-maps", func() {
+			c.Expect(E([]string{"user_id"}, HasKey, "user_id")).Matches(GivesError("type error: expected a map, but was “[user_id]” of type “[]string”"))
+		})
+	})
+
+	c.Specify("Matcher: HasValue", func() {
+		values := map[string]int{"a": 1, "b": 42}
+
+		c.Expect(E(values, HasValue, 1)).Matches(Succeeds)
+		c.Expect(E(values, HasValue, 42)).Matches(Succeeds)
+		c.Expect(E(values, HasValue, 7)).Matches(FailsWithMessage(
+			"has value “7”",
+			"does NOT have value “7”"))
+
+		c.Specify("values are compared using the Equality interface", func() {
+			values := map[string]DummyStruct{"a": DummyStruct{42, 1}}
+			c.Expect(E(values, HasValue, DummyStruct{42, 2})).Matches(Succeeds)
+			c.Expect(E(values, HasValue, DummyStruct{999, 1})).Matches(Fails)
+		})
+		c.Specify("cannot check non-maps", func() {
+			c.Expect(E([]int{42}, HasValue, 42)).Matches(GivesError("type error: expected a map, but was “[42]” of type “[]int”"))
+		})
+	})
+
+	c.Specify("Matcher: HasEntry", func() {
+		values := map[string]string{"foo": "bar"}
+
+		c.Expect(E(values, HasEntry("foo", "bar"))).Matches(Succeeds)
+
+		c.Specify("when the key is missing", func() {
+			c.Expect(E(values, HasEntry("baz", "bar"))).Matches(FailsWithMessage(
+				"has entry “baz”: “bar”, but the key was not present",
+				"does NOT have entry “baz”: “bar”"))
+		})
+		c.Specify("when the value is different", func() {
+			c.Expect(E(values, HasEntry("foo", "baz"))).Matches(FailsWithMessage(
+				"has entry “foo”: “baz”, but the key's value was different",
+				"does NOT have entry “foo”: “baz”"))
+
+			_, pos, _, _ := HasEntry("foo", "baz")(values, nil)
+			c.Expect(pos.Actual()).Equals("bar")
+		})
+		c.Specify("cannot check non-maps", func() {
+			c.Expect(E([]string{"foo"}, HasEntry("foo", "bar"))).Matches(GivesError("type error: expected a map, but was “[foo]” of type “[]string”"))
+		})
+	})
+
+	c.Specify("Matcher: HasField", func() {
+		user := DummyUser{Name: "Bob", Active: true}
+
+		c.Expect(E(user, HasField("Name", "Bob"))).Matches(Succeeds)
+		c.Expect(E(user, HasField("Name", "Alice"))).Matches(FailsWithMessage(
+			"has field “Name”: “Alice”",
+			"does NOT have field “Name”: “Alice”"))
+
+		_, pos, _, _ := HasField("Name", "Alice")(user, nil)
+		c.Expect(pos.Actual()).Equals("Bob")
+
+		c.Specify("works with Not", func() {
+			c.Expect(E(user, Not(HasField("Active", false)))).Matches(Succeeds)
+			c.Expect(E(user, Not(HasField("Active", true)))).Matches(Fails)
+		})
+		c.Specify("pointers to structs", func() {
+			c.Expect(E(&user, HasField("Name", "Bob"))).Matches(Succeeds)
+			c.Expect(E(&user, HasField("Name", "Alice"))).Matches(Fails)
+		})
+		c.Specify("fields of embedded structs", func() {
+			user.ID = 42
+			c.Expect(E(user, HasField("ID", 42))).Matches(Succeeds)
+			c.Expect(E(user, HasField("DummyUserMeta", DummyUserMeta{42}))).Matches(Succeeds)
+			c.Expect(E(DummyUserWithPointer{&DummyUserMeta{42}}, HasField("ID", 42))).Matches(Succeeds)
+		})
+		c.Specify("fields are compared using the Equality interface", func() {
+			c.Expect(E(struct{ Value DummyStruct }{DummyStruct{1, 1}}, HasField("Value", DummyStruct{1, 2}))).Matches(Succeeds)
+		})
+		c.Specify("cannot check unexported fields", func() {
+			c.Expect(E(user, HasField("password", ""))).Matches(GivesError(
+				"illegal argument: the field “password” of “gospec.DummyUser” is not exported"))
+		})
+		c.Specify("cannot check missing fields", func() {
+			c.Expect(E(user, HasField("Age", 30))).Matches(GivesError(
+				"illegal argument: “gospec.DummyUser” has no field “Age”"))
+		})
+		c.Specify("cannot check fields through nil embedded pointers", func() {
+			c.Expect(E(DummyUserWithPointer{}, HasField("ID", 0))).Matches(GivesError(
+				"illegal argument: cannot get the field “ID” of “gospec.DummyUserWithPointer”: reflect: indirection through nil pointer to embedded struct field DummyUserMeta"))
+		})
+		c.Specify("cannot check non-structs", func() {
+			c.Expect(E("Bob", HasField("Name", "Bob"))).Matches(GivesError(
+				"type error: expected a struct, but was “Bob” of type “string”"))
+			c.Expect(E((*DummyUser)(nil), HasField("Name", "Bob"))).Matches(GivesError(
+				"type error: expected a struct, but was “<nil>” of type “*gospec.DummyUser”"))
+		})
+	})
+
+	c.Specify("Matcher: HasFieldOfType", func() {
+		user := DummyUser{Name: "Bob"}
+
+		c.Expect(E(user, HasFieldOfType("Name", ""))).Matches(Succeeds)
+		c.Expect(E(user, HasFieldOfType("Name", 0))).Matches(FailsWithMessage(
+			"has field “Name” of type “int”",
+			"does NOT have field “Name” of type “int”"))
+
+		_, pos, _, _ := HasFieldOfType("Name", 0)(user, nil)
+		c.Expect(fmt.Sprint(pos.Actual())).Equals("string")
+
+		c.Specify("pointer fields", func() {
+			c.Expect(E(&user, HasFieldOfType("Friend", &DummyUser{}))).Matches(Succeeds)
+			c.Expect(E(&user, HasFieldOfType("Friend", DummyUser{}))).Matches(Fails)
+		})
+		c.Specify("fields of embedded structs", func() {
+			c.Expect(E(user, HasFieldOfType("ID", 0))).Matches(Succeeds)
+			c.Expect(E(user, HasFieldOfType("DummyUserMeta", DummyUserMeta{}))).Matches(Succeeds)
+		})
+		c.Specify("interface fields have the type of their value", func() {
+			user.Contact = "bob@example.com"
+			c.Expect(E(user, HasFieldOfType("Contact", ""))).Matches(Succeeds)
+			user.Contact = 42.0
+			c.Expect(E(user, HasFieldOfType("Contact", ""))).Matches(FailsWithMessage(
+				"has field “Contact” of type “string”",
+				"does NOT have field “Contact” of type “string”"))
+		})
+		c.Specify("nil interface fields have the type of the field", func() {
+			_, pos, _, _ := HasFieldOfType("Contact", "")(user, nil)
+			c.Expect(fmt.Sprint(pos.Actual())).Equals("interface {}")
+		})
+		c.Specify("cannot check unexported or missing fields", func() {
+			c.Expect(E(user, HasFieldOfType("password", ""))).Matches(GivesError(
+				"illegal argument: the field “password” of “gospec.DummyUser” is not exported"))
+			c.Expect(E(user, HasFieldOfType("Age", 0))).Matches(GivesError(
+				"illegal argument: “gospec.DummyUser” has no field “Age”"))
+		})
+		c.Specify("cannot check non-structs", func() {
+			c.Expect(E("Bob", HasFieldOfType("Name", ""))).Matches(GivesError(
+				"type error: expected a struct, but was “Bob” of type “string”"))
+		})
 	})
 
 	c.Specify("Conversions for containment matchers", func() {
@@ -269,6 +1915,16 @@ func MatchersSpec(c nanospec.Context) {
 			c.Expect(result[1]).Equals("two")
 			c.Expect(result[2]).Equals("three")
 		})
+		c.Specify("slice of interfaces to array (copy)", func() {
+			values := []interface{}{"one", 2}
+
+			result, _ := toArray(values)
+			values[0] = "changed"
+
+			c.Expect(len(result)).Equals(2)
+			c.Expect(result[0]).Equals("one")
+			c.Expect(result[1]).Equals(2)
+		})
 		c.Specify("channel to array", func() {
 			values := make(chan string, 10)
 			values <- "one"
@@ -296,12 +1952,32 @@ func MatchersSpec(c nanospec.Context) {
 			c.Expect(result[1]).Equals("two")
 			c.Expect(result[2]).Equals("three")
 		})
+		c.Specify("map values to array", func() {
+			values := map[int]string{3: "three", 1: "one", 2: "two"}
+
+			result, _ := toArray(values)
+
+			c.Expect(len(result)).Equals(3)
+			c.Expect(result[0]).Equals("one")
+			c.Expect(result[1]).Equals("two")
+			c.Expect(result[2]).Equals("three")
+		})
 		c.Specify("unsupported value to array", func() {
 			_, err := toArray("foo")
 			c.Expect(err.Error()).Equals("type error: expected a collection type, but was “foo” of type “string”")
 		})
 	})
 
+	c.Specify("Containment matchers check the values of maps", func() {
+		values := map[string]int{"a": 1, "b": 2, "c": 2}
+
+		c.Expect(E(values, Contains, 2)).Matches(Succeeds)
+		c.Expect(E(values, Contains, 3)).Matches(Fails)
+		c.Expect(E(values, ContainsAll, Values(1, 2))).Matches(Succeeds)
+		c.Expect(E(values, ContainsExactly, Values(2, 1, 2))).Matches(Succeeds)
+		c.Expect(E(values, ContainsExactly, Values(1, 2))).Matches(Fails)
+	})
+
 	c.Specify("Containment matchers convert the actual value to a printable array "+
 		"when it's not originally printable",
 		func() {
@@ -360,6 +2036,37 @@ func (this DummyStruct) String() string {
 	return fmt.Sprintf("DummyStruct%v", this.value)
 }
 
+// Used by the error matchers' tests
+type DummyError struct {
+	code int
+}
+
+func (this *DummyError) Error() string {
+	return fmt.Sprintf("DummyError%v", this.code)
+}
+
+func (this *DummyError) Code() int {
+	return this.code
+}
+
+// Used by the struct field matchers' tests
+type DummyUser struct {
+	DummyUserMeta
+	Name     string
+	Active   bool
+	Friend   *DummyUser
+	Contact  interface{}
+	password string
+}
+
+type DummyUserMeta struct {
+	ID int
+}
+
+type DummyUserWithPointer struct {
+	*DummyUserMeta
+}
+
 // Test utilities
 
 type ExpectationHolder struct {
@@ -374,7 +2081,7 @@ func E(actual interface{}, matcher Matcher, expected ...interface{}) *Expectatio
 	return &ExpectationHolder{match, pos, neg, err}
 }
 
-func Passes(v interface{}) error {
+func Succeeds(v interface{}) error {
 	ex := v.(*ExpectationHolder)
 	if ex.match && ex.err == nil {
 		return nil
@@ -7,6 +7,7 @@ package gospec
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
 type PrintFormat interface {
@@ -15,16 +16,69 @@ type PrintFormat interface {
 	PrintSummary(passCount int, failCount int)
 }
 
+// PrintFormats which can show the pending and skipped specs. The other
+// PrintFormats show them only when they are the parents of failing specs.
+type notExecutedPrinter interface {
+	PrintPending(nestingLevel int, name string)
+	PrintSkipped(nestingLevel int, name string, reason string)
+}
+
+// PrintFormats which can show all the counts of Stats in the summary.
+// The other PrintFormats are given only the pass and fail counts.
+type statsPrinter interface {
+	PrintStats(stats Stats)
+}
+
+// PrintFormats which can show warnings, such as when only the focused specs
+// were executed. The other PrintFormats do not show them.
+type warningPrinter interface {
+	PrintWarning(message string)
+}
+
+const (
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiReset  = "\033[0m"
+)
+
 // PrintFormat for production use.
 func DefaultPrintFormat(out io.Writer) PrintFormat {
-	return &defaultPrintFormat{out}
+	return &defaultPrintFormat{out, false, true, 0}
+}
+
+// Same as DefaultPrintFormat, but highlights also the passing specs
+// in green and the failing specs in red, using ANSI escape codes.
+func ColorPrintFormat(out io.Writer) PrintFormat {
+	return &defaultPrintFormat{out, true, true, 0}
+}
+
+// Same as DefaultPrintFormat, but without any ANSI escape codes. Suitable
+// for when the output is not a terminal.
+func PlainPrintFormat(out io.Writer) PrintFormat {
+	return &defaultPrintFormat{out, false, false, 0}
 }
 
 type defaultPrintFormat struct {
-	out io.Writer
+	out           io.Writer
+	colorSpecs    bool
+	colorSummary  bool
+	sourceContext int
+}
+
+// PrintFormats which can show the source code around the failures.
+type sourceContextShower interface {
+	ShowSourceContext(lines int)
+}
+
+// Shows the source code line of every failure, and the given number of
+// lines before and after it. Zero hides the source code.
+func (this *defaultPrintFormat) ShowSourceContext(lines int) {
+	this.sourceContext = lines
 }
 
 func (this *defaultPrintFormat) PrintPassing(nestingLevel int, name string) {
+	name = colorize(this.colorSpecs, ansiGreen, name)
 	if nestingLevel == 0 {
 		fmt.Fprintf(this.out, "\n%v\n", name)
 	} else {
@@ -33,8 +87,8 @@ func (this *defaultPrintFormat) PrintPassing(nestingLevel int, name string) {
 }
 
 func (this *defaultPrintFormat) PrintFailing(nestingLevel int, name string, errors []*Error) {
-	// TODO: use colors (red)
-	fmt.Fprintf(this.out, "%v- %v [FAIL]\n\n", indent(nestingLevel), name)
+	name = colorize(this.colorSpecs, ansiRed, name+" [FAIL]")
+	fmt.Fprintf(this.out, "%v- %v\n\n", indent(nestingLevel), name)
 	for _, error := range errors {
 		this.printError(error)
 	}
@@ -53,9 +107,24 @@ func (this *defaultPrintFormat) printError(error *Error) {
 		// the path. And Go methods have the interpunct · in their names.
 		fmt.Fprintf(this.out, "    %v()\n        at %v:%v\n", loc.Name(), loc.File(), loc.Line())
 	}
+	if loc := error.Location(); loc != nil && this.sourceContext > 0 {
+		source := readSourceContext(loc.File(), loc.Line(), this.sourceContext)
+		fmt.Fprint(this.out, indentLines(source, "    "))
+	}
 	fmt.Fprintf(this.out, "\n")
 }
 
+func indentLines(text string, prefix string) string {
+	lines := strings.SplitAfter(text, "\n")
+	s := ""
+	for _, line := range lines {
+		if line != "" {
+			s += prefix + line
+		}
+	}
+	return s
+}
+
 func formatErrorMessage(e *Error) string {
 	s := ""
 	switch e.Type {
@@ -71,17 +140,54 @@ func formatErrorMessage(e *Error) string {
 	return s
 }
 
+func (this *defaultPrintFormat) PrintPending(nestingLevel int, name string) {
+	name = colorize(this.colorSpecs, ansiYellow, "PENDING: "+name)
+	fmt.Fprintf(this.out, "%v- %v\n", indent(nestingLevel), name)
+}
+
+func (this *defaultPrintFormat) PrintSkipped(nestingLevel int, name string, reason string) {
+	name = colorize(this.colorSpecs, ansiYellow, "SKIP: "+name+" — "+reason)
+	fmt.Fprintf(this.out, "%v- %v\n", indent(nestingLevel), name)
+}
+
 func (this *defaultPrintFormat) PrintSummary(passCount int, failCount int) {
-	totalCount := passCount + failCount
+	this.PrintStats(Stats{PassCount: passCount, FailCount: failCount})
+}
 
-	formatStr := "\n%v specs, %v failures\n"
-	if failCount > 0 {
-		formatStr = fmt.Sprintf(formatStr, "%v", "\033[31m%v\033[0m")
+func (this *defaultPrintFormat) PrintStats(stats Stats) {
+	total := fmt.Sprint(stats.TotalCount())
+	failures := fmt.Sprint(stats.FailCount)
+	if stats.FailCount > 0 {
+		failures = colorize(this.colorSummary, ansiRed, failures)
 	} else {
-		formatStr = fmt.Sprintf(formatStr, "\033[32m%v\033[0m", "%v")
+		total = colorize(this.colorSummary, ansiGreen, total)
+	}
+
+	fmt.Fprintf(this.out, "\n%v specs, %v failures%v\n", total, failures, summaryExtras(stats))
+}
+
+// Counts of the specs which were not executed, if there were any.
+func summaryExtras(stats Stats) string {
+	s := ""
+	if stats.PendingCount > 0 {
+		s += fmt.Sprintf(", %v pending", stats.PendingCount)
+	}
+	if stats.SkipCount > 0 {
+		s += fmt.Sprintf(", %v skipped", stats.SkipCount)
 	}
+	return s
+}
+
+func (this *defaultPrintFormat) PrintWarning(message string) {
+	message = colorize(this.colorSummary, ansiYellow, "WARNING: "+message)
+	fmt.Fprintf(this.out, "\n%v\n", message)
+}
 
-	fmt.Fprintf(this.out, formatStr, totalCount, failCount)
+func colorize(enabled bool, color string, text string) string {
+	if !enabled {
+		return text
+	}
+	return color + text + ansiReset
 }
 
 // PrintFormat for use in only tests. Does not print line numbers, colors or
@@ -112,9 +218,24 @@ func (this *simplePrintFormat) printError(error *Error) {
 	}
 }
 
+func (this *simplePrintFormat) PrintPending(nestingLevel int, name string) {
+	fmt.Fprintf(this.out, "%v- PENDING: %v\n", indent(nestingLevel), name)
+}
+
+func (this *simplePrintFormat) PrintSkipped(nestingLevel int, name string, reason string) {
+	fmt.Fprintf(this.out, "%v- SKIP: %v — %v\n", indent(nestingLevel), name, reason)
+}
+
 func (this *simplePrintFormat) PrintSummary(passCount int, failCount int) {
-	totalCount := passCount + failCount
-	fmt.Fprintf(this.out, "\n%v specs, %v failures\n", totalCount, failCount)
+	this.PrintStats(Stats{PassCount: passCount, FailCount: failCount})
+}
+
+func (this *simplePrintFormat) PrintStats(stats Stats) {
+	fmt.Fprintf(this.out, "\n%v specs, %v failures%v\n", stats.TotalCount(), stats.FailCount, summaryExtras(stats))
+}
+
+func (this *simplePrintFormat) PrintWarning(message string) {
+	fmt.Fprintf(this.out, "\nWARNING: %v\n", message)
 }
 
 func indent(level int) string {
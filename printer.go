@@ -4,7 +4,11 @@
 
 package gospec
 
-import ()
+import (
+	"io"
+	"os"
+	"time"
+)
 
 type printMode int
 
@@ -14,6 +18,7 @@ const (
 )
 
 // Printer formats the spec results into a human-readable format.
+// It can be used both as a Reporter and as a ResultVisitor.
 type Printer struct {
 	format      PrintFormat
 	show        printMode
@@ -30,6 +35,20 @@ func NewPrinter(format PrintFormat) *Printer {
 	}
 }
 
+// Printer which highlights the passing and failing specs with colors.
+// The output must be a terminal which understands ANSI escape codes.
+func NewColorReporter(out io.Writer) *Printer {
+	return NewPrinter(ColorPrintFormat(out))
+}
+
+// Reporter which prints the failing specs and a summary to stdout,
+// the same way as when the specs are run with gotest.
+func DefaultReporter() Reporter {
+	printer := NewPrinter(DefaultPrintFormat(os.Stdout))
+	printer.ShowOnlyFailing()
+	return printer
+}
+
 func (this *Printer) ShowAll() {
 	this.show = ALL
 }
@@ -38,6 +57,14 @@ func (this *Printer) ShowOnlyFailing() {
 	this.show = ONLY_FAILING
 }
 
+// Shows the source code around the failures, the given number of lines
+// before and after the line of the failure, if the PrintFormat supports it.
+func (this *Printer) ShowSourceContext(lines int) {
+	if format, ok := this.format.(sourceContextShower); ok {
+		format.ShowSourceContext(lines)
+	}
+}
+
 func (this *Printer) HideSummary() {
 	this.showSummary = false
 }
@@ -64,8 +91,60 @@ func (this *Printer) VisitSpec(nestingLevel int, name string, errors []*Error) {
 }
 
 func (this *Printer) VisitEnd(passCount int, failCount int) {
-	if this.showSummary {
-		this.format.PrintSummary(passCount, failCount)
+	this.printSummary(Stats{PassCount: passCount, FailCount: failCount})
+}
+
+func (this *Printer) printSummary(stats Stats) {
+	if !this.showSummary {
+		return
+	}
+	if format, ok := this.format.(statsPrinter); ok {
+		format.PrintStats(stats)
+	} else {
+		this.format.PrintSummary(stats.PassCount, stats.FailCount)
+	}
+}
+
+func (this *Printer) SpecStarted(nestingLevel int, name string) {
+}
+
+func (this *Printer) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.VisitSpec(nestingLevel, name, []*Error{})
+}
+
+func (this *Printer) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	this.VisitSpec(nestingLevel, name, errors)
+}
+
+func (this *Printer) SpecSkipped(nestingLevel int, name string, reason string) {
+	if format, ok := this.format.(notExecutedPrinter); ok && this.show == ALL {
+		format.PrintSkipped(nestingLevel, name, reason)
+	} else {
+		this.saveNotPrinted(nestingLevel, name)
+	}
+}
+
+func (this *Printer) SpecPending(nestingLevel int, name string) {
+	if format, ok := this.format.(notExecutedPrinter); ok && this.show == ALL {
+		format.PrintPending(nestingLevel, name)
+	} else {
+		this.saveNotPrinted(nestingLevel, name)
+	}
+}
+
+func (this *Printer) SuiteFinished(stats Stats) {
+	this.printSummary(stats)
+	if stats.Focused {
+		this.printWarning("Only focused specs were run")
+	}
+	if stats.Stopped {
+		this.printWarning("Stopped after first failure.")
+	}
+}
+
+func (this *Printer) printWarning(message string) {
+	if format, ok := this.format.(warningPrinter); ok {
+		format.PrintWarning(message)
 	}
 }
 
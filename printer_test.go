@@ -7,6 +7,7 @@ package gospec
 import (
 	"bytes"
 	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"os"
 	"strings"
 )
 
@@ -130,4 +131,184 @@ func PrinterSpec(c nanospec.Context) {
 `))
 		})
 	})
+
+	c.Specify("When using colors", func() {
+		out := new(bytes.Buffer)
+		p := NewColorReporter(out)
+		p.ShowAll()
+		p.VisitSpec(0, "Passing", noErrors)
+		p.VisitSpec(1, "Failing", someError)
+		p.VisitEnd(1, 1)
+
+		c.Specify("then passing specs are green", func() {
+			c.Expect(strings.Contains(out.String(), "\033[32mPassing\033[0m")).IsTrue()
+		})
+		c.Specify("then failing specs are red", func() {
+			c.Expect(strings.Contains(out.String(), "- \033[31mFailing [FAIL]\033[0m")).IsTrue()
+		})
+		c.Specify("then the failure count is red", func() {
+			c.Expect(strings.Contains(out.String(), "2 specs, \033[31m1\033[0m failures")).IsTrue()
+		})
+	})
+
+	c.Specify("When not using colors", func() {
+		out := new(bytes.Buffer)
+		p := NewPrinter(PlainPrintFormat(out))
+		p.ShowAll()
+		p.VisitSpec(0, "Passing", noErrors)
+		p.VisitSpec(1, "Failing", someError)
+		p.VisitEnd(1, 1)
+
+		c.Specify("then there are no escape codes", func() {
+			c.Expect(strings.Contains(out.String(), "\033")).IsFalse()
+			c.Expect(strings.Contains(out.String(), "2 specs, 1 failures")).IsTrue()
+		})
+	})
+
+	c.Specify("When showing the source context", func() {
+		out := new(bytes.Buffer)
+		p := NewPrinter(PlainPrintFormat(out))
+		p.ShowSourceContext(1)
+		results := runSpec(func(c Context) {
+			c.Expect(1, Equals, 2) // the failing line
+		})
+		results.Visit(p)
+
+		c.Specify("then the line of the failure is shown with the lines around it", func() {
+			c.Expect(strings.Count(out.String(), "    >> ")).Equals(1)
+			c.Expect(strings.Contains(out.String(), "c.Expect(1, Equals, 2) // the failing line\n")).IsTrue()
+			c.Expect(strings.Contains(out.String(), "results := runSpec(func(c Context) {\n")).IsTrue()
+			c.Expect(strings.Contains(out.String(), "results.Visit(p)")).IsFalse()
+		})
+	})
+
+	c.Specify("The color flag chooses the print format", func() {
+		c.Expect(printFormatFor("always", os.Stdout)).Equals(ColorPrintFormat(os.Stdout))
+		c.Expect(printFormatFor("never", os.Stdout)).Equals(PlainPrintFormat(os.Stdout))
+
+		file, err := os.CreateTemp("", "gospec")
+		c.Expect(err).Equals(nil)
+		defer os.Remove(file.Name())
+		defer file.Close()
+		c.Expect(printFormatFor("auto", file)).Equals(PlainPrintFormat(file))
+	})
+
+	c.Specify("When used as a Reporter", func() {
+		p.ShowAll()
+
+		c.Specify("then the results are printed the same way", func() {
+			reportSpec(p, 0, "Passing", noErrors, 0)
+			reportSpec(p, 1, "Failing", someError, 0)
+			p.SpecStarted(1, "Skipped")
+			p.SpecSkipped(1, "Skipped", "not supported")
+			p.SuiteFinished(Stats{PassCount: 1, FailCount: 1, SkipCount: 1})
+			c.Expect(trim(out.String())).Equals(trim(`
+- Passing
+  - Failing [FAIL]
+*** some error
+  - SKIP: Skipped — not supported
+
+3 specs, 1 failures, 1 skipped
+`))
+		})
+	})
+
+	c.Specify("The default reporter is a printer which shows only failing specs", func() {
+		printer, ok := DefaultReporter().(*Printer)
+		c.Expect(ok).IsTrue()
+		c.Expect(printer.show).Equals(ONLY_FAILING)
+		c.Expect(printer.showSummary).IsTrue()
+	})
+
+	c.Specify("When only focused specs were run", func() {
+		p.ShowAll()
+
+		c.Specify("then a warning is printed after the summary", func() {
+			reportSpec(p, 0, "Focused", noErrors, 0)
+			p.SuiteFinished(Stats{PassCount: 1, Focused: true})
+			c.Expect(trim(out.String())).Equals(trim(`
+- Focused
+
+1 specs, 0 failures
+
+WARNING: Only focused specs were run
+`))
+		})
+	})
+
+	c.Specify("When the execution was stopped after the first failure", func() {
+		c.Specify("then a warning is printed after the summary", func() {
+			p.SuiteFinished(Stats{FailCount: 1, Stopped: true})
+			c.Expect(trim(out.String())).Equals(trim(`
+1 specs, 1 failures
+
+WARNING: Stopped after first failure.
+`))
+		})
+	})
+
+	c.Specify("When there are pending specs", func() {
+		c.Specify("then they are printed with the passing specs", func() {
+			p.ShowAll()
+			reportSpec(p, 0, "RootSpec", noErrors, 0)
+			p.SpecStarted(1, "Not implemented")
+			p.SpecPending(1, "Not implemented")
+			p.SuiteFinished(Stats{PassCount: 1, PendingCount: 1})
+			c.Expect(trim(out.String())).Equals(trim(`
+- RootSpec
+  - PENDING: Not implemented
+
+2 specs, 0 failures, 1 pending
+`))
+		})
+		c.Specify("then they are not printed when showing only failing specs", func() {
+			p.ShowOnlyFailing()
+			p.SpecStarted(0, "Not implemented")
+			p.SpecPending(0, "Not implemented")
+			p.SuiteFinished(Stats{PendingCount: 1})
+			c.Expect(trim(out.String())).Equals("1 specs, 0 failures, 1 pending")
+		})
+	})
+
+	c.Specify("When the PrintFormat has only the methods of the PrintFormat interface", func() {
+		p := NewPrinter(minimalPrintFormat{SimplePrintFormat(out)})
+		p.ShowAll()
+
+		c.Specify("then the pending and skipped specs are printed only as parents of failing specs", func() {
+			reportSpec(p, 0, "RootSpec", noErrors, 0)
+			p.SpecStarted(1, "Not implemented")
+			p.SpecPending(1, "Not implemented")
+			p.SpecStarted(1, "Skipped")
+			p.SpecSkipped(1, "Skipped", "not supported")
+			reportSpec(p, 2, "Failing", someError, 0)
+			c.Expect(trim(out.String())).Equals(trim(`
+- RootSpec
+  - Skipped
+    - Failing [FAIL]
+*** some error
+`))
+		})
+		c.Specify("then the summary has only the pass and fail counts, without warnings", func() {
+			p.SuiteFinished(Stats{PassCount: 1, FailCount: 1, PendingCount: 1, Focused: true})
+			c.Expect(trim(out.String())).Equals("2 specs, 1 failures")
+		})
+	})
+}
+
+// PrintFormat which has none of the optional methods, such as PrintPending
+// and PrintStats.
+type minimalPrintFormat struct {
+	format PrintFormat
+}
+
+func (this minimalPrintFormat) PrintPassing(nestingLevel int, name string) {
+	this.format.PrintPassing(nestingLevel, name)
+}
+
+func (this minimalPrintFormat) PrintFailing(nestingLevel int, name string, errors []*Error) {
+	this.format.PrintFailing(nestingLevel, name, errors)
+}
+
+func (this minimalPrintFormat) PrintSummary(passCount int, failCount int) {
+	this.format.PrintSummary(passCount, failCount)
 }
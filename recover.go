@@ -23,14 +23,19 @@ func (this *exception) String() string {
 }
 
 func recoverOnPanic(f func()) (err *exception) {
+	// The value of recover() is nil also after panic(nil) when the panicnil
+	// GODEBUG setting is on, so it cannot tell whether there was a panic.
+	completed := false
 	defer func() {
-		if cause := recover(); cause != nil {
-			callers := stackTraceOfPanic()
-			callers = cutStackTraceAt(recoverOnPanic, callers)
-			err = &exception{cause, asLocationArray(callers)}
+		if !completed {
+			cause := recover()
+			stacktrace := asLocationArray(stackTraceOfPanic())
+			stacktrace = cutStackTraceAt(recoverOnPanic, stacktrace)
+			err = &exception{cause, stacktrace}
 		}
 	}()
 	f()
+	completed = true
 	return
 }
 
@@ -51,22 +56,30 @@ func stackTraceOfPanic() []uintptr {
 	return callers
 }
 
-func cutStackTraceAt(cutpoint_ interface{}, callers []uintptr) []uintptr {
-	cutpoint := functionToFunc(cutpoint_).Entry()
-
-	for i, ptr := range callers {
-		current := runtime.FuncForPC(ptr).Entry()
-		if current == cutpoint {
-			return callers[0:i]
+func cutStackTraceAt(cutpoint interface{}, stacktrace []*Location) []*Location {
+	name := functionName(cutpoint)
+	for i, loc := range stacktrace {
+		if loc.name == name {
+			return stacktrace[0:i]
 		}
 	}
-	return callers
+	return stacktrace
 }
 
+// Every frame of a function which the compiler has inlined into its caller
+// becomes a separate Location, so that the frames can be counted the same way
+// regardless of compiler optimizations.
 func asLocationArray(pcs []uintptr) []*Location {
-	result := make([]*Location, len(pcs))
-	for i, pc := range pcs {
-		result[i] = locationForPC(pc)
+	result := make([]*Location, 0, len(pcs))
+	if len(pcs) == 0 {
+		return result
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		result = append(result, locationOfFrame(frame))
+		if !more {
+			return result
+		}
 	}
-	return result
 }
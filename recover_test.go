@@ -45,6 +45,14 @@ func RecoverSpec(c nanospec.Context) {
 		})
 	})
 
+	c.Specify("When the called function panics with nil", func() {
+		err := recoverOnPanic(func() { panic(nil) })
+
+		c.Specify("the panic is detected", func() {
+			c.Expect(err == nil).IsFalse()
+		})
+	})
+
 	c.Specify("When the called function does not panic", func() {
 		err := recoverOnPanic(noBoom)
 
@@ -0,0 +1,67 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"sync"
+)
+
+var registeredMatchers = newMatcherRegistry()
+
+func init() {
+	builtins := []Matcher{
+		Equals, DeepEquals, DeepEqualsWithDiff, IsSame, IsNil, IsTrue, IsFalse, Satisfies,
+		IsGreaterThan, IsGreaterThanOrEqualTo, IsLessThan, IsLessThanOrEqualTo,
+		IsPositive, IsNegative, IsNonNegative, IsZero, IsNaN, IsInfinite, IsEven, IsOdd,
+		Contains, ContainsAll, ContainsAny, ContainsExactly, ContainsInOrder, ContainsInPartialOrder,
+		IsSorted, IsMonotonicallyIncreasing, IsMonotonicallyDecreasing, IsStrictlyIncreasing, IsStrictlyDecreasing,
+		IsPermutationOf, IsDistinct, HasLength, IsEmpty, HasExactlyOneElement,
+		EqualsFold, Panics, IsOfType, Implements, HasKey, HasValue,
+	}
+	for _, matcher := range builtins {
+		registeredMatchers.register(shortFunctionName(functionName(matcher)), matcher)
+	}
+}
+
+// Registers a matcher by name, so that it can be looked up with LookupMatcher,
+// for example in data-driven specs which read the name of the matcher from a
+// file. The built-in matchers which take no parameters are registered by
+// their function names, for example "Equals". Panics if the name has
+// already been registered.
+func RegisterMatcher(name string, matcher Matcher) {
+	registeredMatchers.register(name, matcher)
+}
+
+// Returns the matcher which was registered with the name,
+// or false if there is no such matcher.
+func LookupMatcher(name string) (Matcher, bool) {
+	return registeredMatchers.lookup(name)
+}
+
+type matcherRegistry struct {
+	mutex    sync.RWMutex
+	matchers map[string]Matcher
+}
+
+func newMatcherRegistry() *matcherRegistry {
+	return &matcherRegistry{matchers: make(map[string]Matcher)}
+}
+
+func (this *matcherRegistry) register(name string, matcher Matcher) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if _, exists := this.matchers[name]; exists {
+		panic(fmt.Sprintf("a matcher named “%v” has already been registered", name))
+	}
+	this.matchers[name] = matcher
+}
+
+func (this *matcherRegistry) lookup(name string) (Matcher, bool) {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	matcher, ok := this.matchers[name]
+	return matcher, ok
+}
@@ -0,0 +1,54 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+)
+
+func RegistrySpec(c nanospec.Context) {
+
+	c.Specify("The built-in matchers are registered by their function names", func() {
+		matcher, ok := LookupMatcher("Equals")
+		c.Expect(ok).IsTrue()
+		match, _, _, _ := matcher(1, 1)
+		c.Expect(match).IsTrue()
+		match, _, _, _ = matcher(1, 2)
+		c.Expect(match).IsFalse()
+
+		_, ok = LookupMatcher("IsEmpty")
+		c.Expect(ok).IsTrue()
+	})
+	c.Specify("Looking up an unregistered name fails", func() {
+		_, ok := LookupMatcher("NoSuchMatcher")
+		c.Expect(ok).IsFalse()
+	})
+
+	c.Specify("Given a registry", func() {
+		registry := newMatcherRegistry()
+
+		c.Specify("a registered matcher can be looked up by its name", func() {
+			registry.register("IsFortyTwo", IsFortyTwo)
+			matcher, ok := registry.lookup("IsFortyTwo")
+			c.Expect(ok).IsTrue()
+			match, _, _, _ := matcher(42, nil)
+			c.Expect(match).IsTrue()
+		})
+		c.Specify("registering the same name twice panics", func() {
+			registry.register("IsFortyTwo", IsFortyTwo)
+			var cause interface{}
+			panicked := CapturePanic(func() { registry.register("IsFortyTwo", Equals) }, &cause)
+			c.Expect(panicked).IsTrue()
+			c.Expect(cause).Equals("a matcher named “IsFortyTwo” has already been registered")
+		})
+	})
+}
+
+func IsFortyTwo(actual interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+	match = actual == 42
+	pos = Messagef(actual, "is 42")
+	neg = Messagef(actual, "is NOT 42")
+	return
+}
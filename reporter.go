@@ -0,0 +1,107 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Reporter receives the results of the specs, for example to print them or
+// to write them to a file. The specs are reported in the order in which they
+// were declared. For every spec, SpecStarted is called first, followed by
+// the call which tells the spec's outcome. The nesting level of root specs
+// is zero. When all specs have been reported, SuiteFinished is called.
+// Pending specs are specs which have not yet been implemented.
+type Reporter interface {
+	SpecStarted(nestingLevel int, name string)
+	SpecPassed(nestingLevel int, name string, duration time.Duration)
+	SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration)
+	SpecSkipped(nestingLevel int, name string, reason string)
+	SpecPending(nestingLevel int, name string)
+	SuiteFinished(stats Stats)
+}
+
+// Statistics of all the reported specs. The duration is the total execution
+// time of all the root specs. Focused is true if only the focused specs
+// were executed, and Stopped is true if the execution was stopped after
+// the first failure.
+type Stats struct {
+	PassCount    int
+	FailCount    int
+	SkipCount    int
+	PendingCount int
+	Duration     time.Duration
+	Focused      bool
+	Stopped      bool
+}
+
+func (this Stats) TotalCount() int {
+	return this.PassCount + this.FailCount + this.SkipCount + this.PendingCount
+}
+
+// MultiReporter forwards every call to all of its reporters, so that the
+// results can be reported in many formats at the same time. If one of the
+// reporters panics, the other reporters will still get all the calls.
+// The panics are collected and can be retrieved with Err.
+type MultiReporter struct {
+	reporters []Reporter
+	errs      []error
+}
+
+func NewMultiReporter(reporters ...Reporter) *MultiReporter {
+	return &MultiReporter{reporters, []error{}}
+}
+
+func (this *MultiReporter) SpecStarted(nestingLevel int, name string) {
+	this.forEach(func(r Reporter) { r.SpecStarted(nestingLevel, name) })
+}
+
+func (this *MultiReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.forEach(func(r Reporter) { r.SpecPassed(nestingLevel, name, duration) })
+}
+
+func (this *MultiReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	this.forEach(func(r Reporter) { r.SpecFailed(nestingLevel, name, errors, duration) })
+}
+
+func (this *MultiReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	this.forEach(func(r Reporter) { r.SpecSkipped(nestingLevel, name, reason) })
+}
+
+func (this *MultiReporter) SpecPending(nestingLevel int, name string) {
+	this.forEach(func(r Reporter) { r.SpecPending(nestingLevel, name) })
+}
+
+func (this *MultiReporter) SuiteFinished(stats Stats) {
+	this.forEach(func(r Reporter) { r.SuiteFinished(stats) })
+}
+
+// Returns the panics of the reporters as one error,
+// or nil if none of the reporters panicked.
+func (this *MultiReporter) Err() error {
+	return errors.Join(this.errs...)
+}
+
+func (this *MultiReporter) forEach(call func(Reporter)) {
+	for _, reporter := range this.reporters {
+		reporter := reporter
+		if e := recoverOnPanic(func() { call(reporter) }); e != nil {
+			this.errs = append(this.errs, fmt.Errorf("%T: %v", reporter, e.Cause))
+		}
+	}
+}
+
+// Writes immediately also through buffered writers, so that the output is
+// not left in the buffer while the other specs are still being executed.
+func flushIfBuffered(out io.Writer) {
+	if flusher, ok := out.(interface {
+		Flush() error
+	}); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+	"time"
+)
+
+func ReporterSpec(c nanospec.Context) {
+
+	c.Specify("Results are reported in declaration order", func() {
+		recorder := new(recordingReporter)
+		results := runSpec(func(c Context) {
+			c.Specify("Passing", func() {})
+			c.Specify("Failing", func() {
+				c.Expect(1, Equals, 2)
+			})
+		})
+		results.Report(recorder)
+
+		c.Expect(recorder.String()).Equals("" +
+			"started 0 RootSpec\n" +
+			"passed 0 RootSpec\n" +
+			"started 1 Passing\n" +
+			"passed 1 Passing\n" +
+			"started 1 Failing\n" +
+			"failed 1 Failing 1\n" +
+			"finished 2 1 0\n")
+	})
+
+	c.Specify("Runner.ReportTo reports the same results as ResultCollector.Report", func() {
+		streamed := new(recordingReporter)
+		r := NewRunner()
+		r.AddNamedSpec("B", func(c Context) {
+			c.Specify("Passing", func() {})
+		})
+		r.AddNamedSpec("A", func(c Context) {
+			c.Specify("Failing in AfterAll", func() {
+				c.AfterAll(func() {
+					panic("boom")
+				})
+			})
+			c.Specify("Passing", func() {})
+		})
+		r.ReportTo(streamed)
+		r.Run()
+		reported := new(recordingReporter)
+		r.Results().Report(reported)
+
+		c.Expect(streamed.String()).Equals("" +
+			"started 0 A\n" +
+			"passed 0 A\n" +
+			"started 1 Failing in AfterAll\n" +
+			"failed 1 Failing in AfterAll 1\n" +
+			"started 1 Passing\n" +
+			"passed 1 Passing\n" +
+			"started 0 B\n" +
+			"passed 0 B\n" +
+			"started 1 Passing\n" +
+			"passed 1 Passing\n" +
+			"finished 4 1 0\n")
+		c.Expect(streamed.String()).Equals(reported.String())
+	})
+
+	c.Specify("MultiReporter forwards all calls to every reporter", func() {
+		r1 := new(recordingReporter)
+		r2 := new(recordingReporter)
+		multi := NewMultiReporter(r1, r2)
+
+		reportSpec(multi, 0, "RootSpec", noErrors, 0)
+		multi.SpecStarted(1, "Skipped")
+		multi.SpecSkipped(1, "Skipped", "reason")
+		multi.SuiteFinished(Stats{PassCount: 1, SkipCount: 1})
+
+		expected := "" +
+			"started 0 RootSpec\n" +
+			"passed 0 RootSpec\n" +
+			"started 1 Skipped\n" +
+			"skipped 1 Skipped reason\n" +
+			"finished 1 0 1\n"
+		c.Expect(r1.String()).Equals(expected)
+		c.Expect(r2.String()).Equals(expected)
+		c.Expect(multi.Err()).Equals(nil)
+	})
+
+	c.Specify("MultiReporter keeps on reporting when a reporter panics", func() {
+		recorder := new(recordingReporter)
+		multi := NewMultiReporter(panickingReporter{recorder}, recorder)
+
+		reportSpec(multi, 0, "RootSpec", noErrors, 0)
+		multi.SuiteFinished(Stats{PassCount: 1})
+
+		c.Expect(recorder.String()).Equals("" +
+			"started 0 RootSpec\n" +
+			"started 0 RootSpec\n" +
+			"passed 0 RootSpec\n" +
+			"finished 1 0 0\n")
+		c.Expect(multi.Err() != nil).IsTrue()
+		c.Expect(strings.Contains(multi.Err().Error(), "gospec.panickingReporter: boom")).IsTrue()
+	})
+
+	c.Specify("The failures and durations are passed to the reporter", func() {
+		recorder := new(argumentsReporter)
+		results := runSpec(func(c Context) {
+			c.Expect(1, Equals, 2)
+		})
+		results.Report(recorder)
+
+		c.Expect(len(recorder.errors)).Equals(1)
+		c.Expect(recorder.errors[0].Message).Equals("equals “2”")
+		c.Expect(recorder.errors[0].Actual).Equals("1")
+		c.Expect(recorder.duration >= 0).IsTrue()
+		c.Expect(recorder.stats.FailCount).Equals(1)
+		c.Expect(recorder.stats.Duration).Equals(recorder.duration)
+	})
+}
+
+type recordingReporter struct {
+	events []string
+}
+
+func (this *recordingReporter) record(format string, args ...interface{}) {
+	this.events = append(this.events, fmt.Sprintf(format, args...))
+}
+
+func (this *recordingReporter) String() string {
+	return strings.Join(this.events, "\n") + "\n"
+}
+
+func (this *recordingReporter) SpecStarted(nestingLevel int, name string) {
+	this.record("started %v %v", nestingLevel, name)
+}
+
+func (this *recordingReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.record("passed %v %v", nestingLevel, name)
+}
+
+func (this *recordingReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	this.record("failed %v %v %v", nestingLevel, name, len(errors))
+}
+
+func (this *recordingReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	this.record("skipped %v %v %v", nestingLevel, name, reason)
+}
+
+func (this *recordingReporter) SpecPending(nestingLevel int, name string) {
+	this.record("pending %v %v", nestingLevel, name)
+}
+
+func (this *recordingReporter) SuiteFinished(stats Stats) {
+	this.record("finished %v %v %v", stats.PassCount, stats.FailCount, stats.SkipCount)
+}
+
+// Remembers the arguments of the last failed spec and finished suite.
+type argumentsReporter struct {
+	recordingReporter
+	errors   []*Error
+	duration time.Duration
+	stats    Stats
+}
+
+func (this *argumentsReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	this.errors = errors
+	this.duration = duration
+}
+
+func (this *argumentsReporter) SuiteFinished(stats Stats) {
+	this.stats = stats
+}
+
+// Records only the started specs and panics on everything else.
+type panickingReporter struct {
+	*recordingReporter
+}
+
+func (this panickingReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	panic("boom")
+}
+
+func (this panickingReporter) SuiteFinished(stats Stats) {
+	panic("boom")
+}
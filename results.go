@@ -8,13 +8,19 @@ import (
 	"container/list"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 )
 
 // Collects test results for all specs in a reporting friendly format.
 type ResultCollector struct {
-	rootsByName map[string]*specResult
-	passCount   int
-	failCount   int
+	rootsByName  map[string]*specResult
+	passCount    int
+	failCount    int
+	skipCount    int
+	pendingCount int
+	focused      bool
+	stopped      bool
 }
 
 func newResultCollector() *ResultCollector {
@@ -22,6 +28,10 @@ func newResultCollector() *ResultCollector {
 		make(map[string]*specResult),
 		-1,
 		-1,
+		-1,
+		-1,
+		false,
+		false,
 	}
 }
 
@@ -44,7 +54,7 @@ func (r *ResultCollector) getOrCreateRoot(spec *specRun) *specResult {
 // Number of specs
 
 func (r *ResultCollector) TotalCount() int {
-	return r.PassCount() + r.FailCount()
+	return r.PassCount() + r.FailCount() + r.SkipCount() + r.PendingCount()
 }
 
 func (r *ResultCollector) PassCount() int {
@@ -61,6 +71,20 @@ func (r *ResultCollector) FailCount() int {
 	return r.failCount
 }
 
+func (r *ResultCollector) SkipCount() int {
+	if r.skipCount < 0 {
+		r.calculateSpecCount()
+	}
+	return r.skipCount
+}
+
+func (r *ResultCollector) PendingCount() int {
+	if r.pendingCount < 0 {
+		r.calculateSpecCount()
+	}
+	return r.pendingCount
+}
+
 func (r *ResultCollector) calculateSpecCount() {
 	r.resetSpecCount()
 	r.visitAll(func(spec *specResult) {
@@ -71,10 +95,16 @@ func (r *ResultCollector) calculateSpecCount() {
 func (r *ResultCollector) resetSpecCount() {
 	r.failCount = 0
 	r.passCount = 0
+	r.skipCount = 0
+	r.pendingCount = 0
 }
 
 func (r *ResultCollector) incrementSpecCount(spec *specResult) {
-	if spec.isFailed() {
+	if spec.isSkipped() {
+		r.skipCount++
+	} else if spec.pending {
+		r.pendingCount++
+	} else if spec.isFailed() {
 		r.failCount++
 	} else {
 		r.passCount++
@@ -92,11 +122,84 @@ func (r *ResultCollector) Visit(visitor ResultVisitor) {
 	r.resetSpecCount()
 	r.visitAll(func(spec *specResult) {
 		r.incrementSpecCount(spec)
+		if spec.isSkipped() || spec.pending {
+			return
+		}
 		visitor.VisitSpec(len(spec.path), spec.name, listToErrorArray(spec.errors))
 	})
 	visitor.VisitEnd(r.passCount, r.failCount)
 }
 
+// Reports the results of all specs to the reporter, in the order in which
+// the specs were declared.
+func (r *ResultCollector) Report(reporter Reporter) {
+	r.resetSpecCount()
+	duration := time.Duration(0)
+	for root := range r.sortedRoots() {
+		duration += r.reportRoot(root, reporter)
+	}
+	reporter.SuiteFinished(r.stats(duration))
+}
+
+// Reports the root spec and its children, and adds them to the spec counts.
+// Returns the duration of the root spec.
+func (r *ResultCollector) reportRoot(root *specResult, reporter Reporter) time.Duration {
+	root.visitAll(func(spec *specResult) {
+		r.incrementSpecCount(spec)
+		nestingLevel := len(spec.path)
+
+		reporter.SpecStarted(nestingLevel, spec.name)
+		if spec.isSkipped() {
+			reporter.SpecSkipped(nestingLevel, spec.name, spec.skipReason)
+		} else if spec.pending {
+			reporter.SpecPending(nestingLevel, spec.name)
+		} else if spec.isFailed() {
+			reporter.SpecFailed(nestingLevel, spec.name, listToErrorArray(spec.errors), spec.duration)
+		} else {
+			reporter.SpecPassed(nestingLevel, spec.name, spec.duration)
+		}
+	})
+	return root.duration
+}
+
+func (r *ResultCollector) stats(duration time.Duration) Stats {
+	return Stats{
+		PassCount:    r.passCount,
+		FailCount:    r.failCount,
+		SkipCount:    r.skipCount,
+		PendingCount: r.pendingCount,
+		Duration:     duration,
+		Focused:      r.focused,
+		Stopped:      r.stopped,
+	}
+}
+
+// Skips the nested specs which were executed only to find out whether their
+// children match the filter. The root specs are never filtered out.
+func (r *ResultCollector) skipFilteredOut() {
+	for _, root := range r.rootsByName {
+		root.skipFilteredOutChildren()
+	}
+}
+
+// Skips all specs except the focused specs, their children and parents.
+func (r *ResultCollector) skipNotFocused() {
+	r.focused = true
+	for _, root := range r.rootsByName {
+		root.skipNotFocused(false)
+	}
+}
+
+// Name of a spec for reports which list the specs without nesting. Consists of
+// the names of the spec and its parents, without the root spec's name unless
+// the spec is the root spec.
+func specPathName(path []string) string {
+	if len(path) == 1 {
+		return path[0]
+	}
+	return strings.Join(path[1:], " / ")
+}
+
 func listToErrorArray(list *list.List) []*Error {
 	arr := make([]*Error, list.Len())
 	i := 0
@@ -137,10 +240,15 @@ func (r *ResultCollector) sortedRootNames() []string {
 
 // Collects test results for one spec and its children in a reporting friendly format.
 type specResult struct {
-	name     string
-	path     path
-	children *list.List
-	errors   *list.List
+	name       string
+	path       path
+	children   *list.List
+	errors     *list.List
+	duration   time.Duration
+	skipReason string
+	focused    bool
+	pending    bool
+	matches    bool
 }
 
 func newSpecResult(spec *specRun) *specResult {
@@ -150,6 +258,11 @@ func newSpecResult(spec *specRun) *specResult {
 		spec.path,
 		list.New(),
 		list.New(),
+		0,
+		"",
+		false,
+		false,
+		false,
 	}
 }
 
@@ -157,6 +270,11 @@ func (this *specResult) isFailed() bool {
 	return this.errors.Len() > 0
 }
 
+// A spec which failed before it was skipped is reported as failed.
+func (this *specResult) isSkipped() bool {
+	return this.skipReason != "" && !this.isFailed()
+}
+
 func (this *specResult) visitAll(visitor func(*specResult)) {
 	visitor(this)
 	for e := this.children.Front(); e != nil; e = e.Next() {
@@ -165,6 +283,46 @@ func (this *specResult) visitAll(visitor func(*specResult)) {
 	}
 }
 
+// Returns true if the spec or some of its parents or children are focused.
+func (this *specResult) skipNotFocused(parentInFocus bool) bool {
+	inFocus := parentInFocus || this.focused
+	hasFocusedChildren := false
+	for e := this.children.Front(); e != nil; e = e.Next() {
+		child := e.Value.(*specResult)
+		if child.skipNotFocused(inFocus) {
+			hasFocusedChildren = true
+		}
+	}
+	if !inFocus && !hasFocusedChildren {
+		this.errors = list.New()
+		this.skipReason = "not focused"
+	}
+	return inFocus || hasFocusedChildren
+}
+
+func (this *specResult) skipFilteredOutChildren() {
+	for e := this.children.Front(); e != nil; e = e.Next() {
+		e.Value.(*specResult).skipFilteredOut(this.matches)
+	}
+}
+
+// Returns true if the spec or some of its parents or children match the filter.
+func (this *specResult) skipFilteredOut(parentMatches bool) bool {
+	matches := parentMatches || this.matches
+	hasMatchingChildren := false
+	for e := this.children.Front(); e != nil; e = e.Next() {
+		child := e.Value.(*specResult)
+		if child.skipFilteredOut(matches) {
+			hasMatchingChildren = true
+		}
+	}
+	if !matches && !hasMatchingChildren {
+		this.errors = list.New()
+		this.skipReason = "filtered out"
+	}
+	return matches || hasMatchingChildren
+}
+
 func (this *specResult) update(spec *specRun) {
 	isMe := this.path.isEqual(spec.path)
 	isMyChild := this.path.isOn(spec.path) && !isMe
@@ -172,6 +330,13 @@ func (this *specResult) update(spec *specRun) {
 
 	if isMe {
 		this.mergeErrors(spec.errors)
+		this.duration += spec.duration
+		if spec.skipReason != "" {
+			this.skipReason = spec.skipReason
+		}
+		this.focused = spec.focused
+		this.pending = spec.pending
+		this.matches = this.matches || spec.matchesFilter
 	}
 	if isMyDirectChild {
 		if !this.isRegisteredChild(spec) {
@@ -231,12 +231,15 @@ func ResultsSpec(c nanospec.Context) {
 *** Expected: equals “20”
          got: “10”
     at results_test.go
+    at results_test.go
 *** Expected: equals “20”
          got: “11”
     at results_test.go
+    at results_test.go
 *** Expected: equals “20”
          got: “12”
     at results_test.go
+    at results_test.go
     - Child A
     - Child B
 
@@ -248,14 +251,14 @@ func ResultsSpec(c nanospec.Context) {
 	c.Specify("When an expectation gives an error", func() {
 		runner := NewRunner()
 		runner.AddNamedSpec("RootSpec", func(c Context) {
-			c.Expect(1, IsWithin(0.1), 1.0)
+			c.Expect("1", IsWithin(0.1), 1.0)
 		})
 		runner.Run()
 
 		c.Specify("the error is reported as-is", func() {
 			c.Expect(runner.Results()).Matches(ReportIs(`
 - RootSpec [FAIL]
-*** type error: expected a float, but was “1” of type “int”
+*** type error: expected a number, but was “1” of type “string”
     at results_test.go
 
 1 specs, 1 failures
@@ -4,57 +4,238 @@
 
 package gospec
 
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
 const (
 	channelBufferSize = 10
 )
 
 // Runner executes the specs and collects their results.
 type Runner struct {
-	runningTasks int
-	results      chan *taskResult
-	executed     []*specRun
-	scheduled    []*scheduledTask
+	runningTasks  int
+	runningSerial bool
+	focusMode     bool
+	failFast      bool
+	fullStacks    bool
+	stopped       bool
+	workers       int
+	filter        *specFilter
+	timeout       time.Duration
+	debug         *log.Logger
+	seed          int64
+	randoms       map[string]*rand.Rand
+	hooks         *onceHooks
+	stream        *streamingReport
+	unfinished    map[string]int
+	results       chan *taskResult
+	executed      []*specRun
+	scheduled     []*scheduledTask
 }
 
 func NewRunner() *Runner {
 	r := new(Runner)
 	r.runningTasks = 0
+	r.runningSerial = false
+	r.focusMode = false
+	r.workers = 0
 	r.results = make(chan *taskResult, channelBufferSize)
 	r.executed = make([]*specRun, 0)
 	r.scheduled = make([]*scheduledTask, 0)
+	r.hooks = newOnceHooks()
+	r.unfinished = make(map[string]int)
 	return r
 }
 
 // Adds a spec for later execution. Example:
 //     r.AddSpec(SomeSpec);
-func (r *Runner) AddSpec(closure func(Context)) {
-	r.AddNamedSpec(functionName(closure), closure)
+//     r.AddSpec(DatabaseSpec, Serial());
+func (r *Runner) AddSpec(closure func(Context), options ...SpecOption) {
+	r.AddNamedSpec(functionName(closure), closure, options...)
 }
 
 // Adds a spec for later execution. Uses the provided name instead of
 // retrieving the name of the spec function with reflection.
-func (r *Runner) AddNamedSpec(name string, closure func(Context)) {
+func (r *Runner) AddNamedSpec(name string, closure func(Context), options ...SpecOption) {
 	task := newScheduledTask(name, closure, newInitialContext())
+	for _, option := range options {
+		option(&task.options)
+	}
 	r.scheduled = append(r.scheduled, task)
+	r.unfinished[name]++
+}
+
+// Skips the nested specs whose full name, which consists of the names of
+// the spec and its parents separated by spaces, does not match the regular
+// expression. The children of a matching spec are also executed. Because the
+// children of a spec are found only by executing it, a spec which does not
+// match is executed if its children might match, and then reported as
+// skipped if none of them did. For example "Stack" executes every spec
+// to find the ones whose names contain "Stack", but "^StackSpec When empty"
+// executes only the specs on that path. Returns an error if the pattern is
+// not valid.
+func (r *Runner) FilterByName(pattern string) error {
+	filter, err := newSpecFilter(pattern)
+	if err != nil {
+		return err
+	}
+	r.filter = filter
+	return nil
+}
+
+// Reports the results to the reporter while the specs are being executed.
+// A root spec is reported as soon as all of its children and the root specs
+// before it have been executed, so that the results are available before
+// the whole suite has finished. The specs are reported in the same order as
+// by ResultCollector.Report. Because focused specs are found only by
+// executing them, the root specs which were reported before the first
+// focused spec was found are not skipped for being out of focus.
+func (r *Runner) ReportTo(reporter Reporter) {
+	r.stream = newStreamingReport(reporter)
+}
+
+// Fails the specs which take longer than the timeout to execute, unless the
+// spec was added with the WithTimeout option. Zero means no timeout.
+func (r *Runner) SetDefaultTimeout(timeout time.Duration) {
+	r.timeout = timeout
+}
+
+// Stops executing the specs after the first failure. The specs are executed
+// one at a time, so that no new specs will be started after the failure.
+func (r *Runner) FailFast() {
+	r.failFast = true
+}
+
+// Includes the frames of GoSpec and the Go runtime in the stack traces of
+// failed expectations. By default they are removed, so that the stack trace
+// shows only the specs and their helper functions.
+func (r *Runner) ShowFullStackTraces() {
+	r.fullStacks = true
+}
+
+// Executes the specs in random order, to find specs which depend on the
+// order in which they are executed. The root specs and the children of every
+// spec are shuffled separately. When the seed is zero, a random seed is
+// chosen. Returns the seed, which can be used to repeat the same order.
+func (r *Runner) RandomizeOrder(seed int64) int64 {
+	for seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	r.seed = seed
+	r.randoms = make(map[string]*rand.Rand)
+	return seed
+}
+
+func (r *Runner) isRandomOrder() bool {
+	return r.seed != 0
+}
+
+// Every root spec has its own random number generator, so that the order
+// of a spec's children does not depend on the other root specs.
+func (r *Runner) shuffle(tasks []*scheduledTask, rootName string) {
+	random, ok := r.randoms[rootName]
+	if !ok {
+		hash := fnv.New64a()
+		hash.Write([]byte(rootName))
+		random = rand.New(rand.NewSource(r.seed ^ int64(hash.Sum64())))
+		r.randoms[rootName] = random
+	}
+	random.Shuffle(len(tasks), func(i, j int) {
+		tasks[i], tasks[j] = tasks[j], tasks[i]
+	})
+}
+
+// Writes debugging information, such as the retries of failed specs,
+// to the output.
+func (r *Runner) SetDebugOutput(out io.Writer) {
+	r.debug = log.New(out, "DEBUG: ", 0)
+}
+
+// SpecOption changes how a spec added with AddSpec is executed.
+type SpecOption func(options *specOptions)
+
+type specOptions struct {
+	serial  bool
+	timeout time.Duration
+	retries int
+}
+
+// Executes the spec so that no other specs are executed at the same time.
+// Useful for specs which share mutable state, such as global variables or
+// an external database.
+func Serial() SpecOption {
+	return func(options *specOptions) {
+		options.serial = true
+	}
+}
+
+// Executes a failing spec again at most the given number of times. The spec
+// passes if any of the attempts passes. Useful for specs which fail
+// randomly because of timing or network problems.
+func WithRetries(retries int) SpecOption {
+	return func(options *specOptions) {
+		options.retries = retries
+	}
+}
+
+// Fails the spec and its children if executing them takes longer than the
+// timeout. The timeout applies separately to every spec, and the time spent
+// executing a spec's children does not count towards the spec's timeout.
+// Because goroutines cannot be stopped, a spec which has timed out keeps on
+// running in the background until it returns, but its results and children
+// are ignored. The specs after it are still executed.
+func WithTimeout(timeout time.Duration) SpecOption {
+	return func(options *specOptions) {
+		options.timeout = timeout
+	}
 }
 
 // Executes all the specs which have been added with AddSpec. The specs
 // are executed using as many goroutines as possible, so that even individual
 // spec methods are executed in multiple goroutines.
 func (r *Runner) Run() {
+	if r.isRandomOrder() {
+		r.shuffle(r.scheduled, "")
+	}
+	if r.stream != nil {
+		r.stream.unreported = r.scheduledRootNames()
+	}
 	r.startAllScheduledTasks()
 	r.startNewTasksAndWaitUntilFinished()
+	r.hooks.runAfterAllHooks()
+	r.finishReport()
+}
+
+// Executes all the specs the same way as Run, but executes at most the given
+// number of spec methods at the same time. The results do not depend on the
+// number of workers, because they are sorted before they are reported.
+func (r *Runner) RunParallel(workers int) {
+	r.workers = workers
+	r.Run()
 }
 
 func (r *Runner) startAllScheduledTasks() {
-	for r.hasScheduledTasks() {
-		r.startNextScheduledTask()
+	for r.canStartMoreTasks() {
+		task := r.nextStartableTask()
+		if task == nil {
+			break
+		}
+		r.start(task)
 	}
 }
 
 func (r *Runner) startNewTasksAndWaitUntilFinished() {
 	for r.hasRunningTasks() {
 		r.processNextFinishedTask()
+		r.reportFinishedRoots()
 		r.startAllScheduledTasks()
 	}
 }
@@ -66,45 +247,178 @@ func (r *Runner) executeNextScheduledTask() {
 }
 
 func (r *Runner) startNextScheduledTask() {
-	task := r.nextScheduledTask()
+	r.start(r.nextScheduledTask())
+}
+
+func (r *Runner) start(task *scheduledTask) {
+	task.context.timeout = r.timeoutOf(task.options)
 	go func() {
-		r.results <- r.execute(task.name, task.closure, task.context)
+		result := r.executeWithRetries(task)
+		result.options = task.options
+		r.results <- result
 	}()
 	r.runningTasks++
+	r.runningSerial = task.options.serial
+}
+
+func (r *Runner) timeoutOf(options specOptions) time.Duration {
+	if options.timeout > 0 {
+		return options.timeout
+	}
+	return r.timeout
 }
 
 func (r *Runner) processNextFinishedTask() {
 	result := <-r.results
 	r.runningTasks--
+	r.runningSerial = false
 	r.saveResult(result)
+	r.unfinished[result.name]--
+	if r.unfinished[result.name] == 0 {
+		r.hooks.runAfterAllHooksOf(result.name)
+	}
 }
 
 func (r *Runner) hasRunningTasks() bool   { return r.runningTasks > 0 }
 func (r *Runner) hasScheduledTasks() bool { return len(r.scheduled) > 0 }
+func (r *Runner) canStartMoreTasks() bool {
+	workers := r.workers
+	if r.failFast {
+		workers = 1
+	}
+	return !r.stopped && !r.runningSerial && (workers <= 0 || r.runningTasks < workers)
+}
 func (r *Runner) nextScheduledTask() *scheduledTask {
-	last := len(r.scheduled) - 1
-	popped := r.scheduled[last]
-	r.scheduled = r.scheduled[:last]
-	return popped
+	return r.removeScheduledTask(len(r.scheduled) - 1)
+}
+
+// Serial tasks can be started only when no other tasks are running.
+func (r *Runner) nextStartableTask() *scheduledTask {
+	for i := len(r.scheduled) - 1; i >= 0; i-- {
+		if !r.scheduled[i].options.serial || !r.hasRunningTasks() {
+			return r.removeScheduledTask(i)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) removeScheduledTask(index int) *scheduledTask {
+	removed := r.scheduled[index]
+	r.scheduled = append(r.scheduled[:index], r.scheduled[index+1:]...)
+	return removed
 }
 
 func (r *Runner) execute(name string, closure specRoot, c *taskContext) *taskResult {
-	c.Specify(name, func() { closure(c) })
+	c.filter = r.filter
+	c.hooks = r.hooks
+	c.fullStacks = r.fullStacks
+	if c.timeout > 0 {
+		// The task finishes when a spec times out, even though the spec
+		// keeps on running in the background until it returns.
+		finished := make(chan bool, 1)
+		go func() {
+			c.Specify(name, func() { closure(c) })
+			finished <- true
+		}()
+		select {
+		case <-finished:
+		case <-c.aborted:
+		}
+	} else {
+		c.Specify(name, func() { closure(c) })
+	}
+	if snapshot := c.snapshot(); snapshot != nil {
+		result := &taskResult{name, closure, specOptions{}, snapshot.executedSpecs, snapshot.postponedSpecs, nil}
+		if snapshot.timedOut.path.isBeyond(c.targetPath) {
+			result.resumed = c.resumeAfter(snapshot.timedOut)
+		}
+		return result
+	}
 	return &taskResult{
 		name,
 		closure,
+		specOptions{},
 		asSpecArray(c.executedSpecs),
 		asSpecArray(c.postponedSpecs),
+		nil,
+	}
+}
+
+// Only the results of the last attempt are used, so that the postponed
+// specs will not be scheduled many times.
+func (r *Runner) executeWithRetries(task *scheduledTask) *taskResult {
+	attempts := task.options.retries + 1
+	context := task.context
+	for attempt := 1; ; attempt++ {
+		result := r.execute(task.name, task.closure, context)
+		failed := result.failedSpecs()
+		if len(failed) == 0 {
+			return result
+		}
+		if attempt == attempts {
+			if attempts > 1 {
+				for _, spec := range failed {
+					message := fmt.Sprintf("Failed after %v attempts", attempts)
+					spec.AddError(newError(OtherError, message, "", []*Location{}))
+				}
+			}
+			return result
+		}
+		r.debugf("Retrying spec '%v' after a failure (attempt %v of %v)", failed[0].fullName(), attempt+1, attempts)
+		context = newExplicitContext(task.context.targetPath)
+		context.timeout = task.context.timeout
+		context.timedOutPaths = task.context.timedOutPaths
+	}
+}
+
+func (r *Runner) debugf(format string, args ...interface{}) {
+	if r.debug != nil {
+		r.debug.Printf(format, args...)
 	}
 }
 
 func (r *Runner) saveResult(result *taskResult) {
+	r.detectFocusedSpecs(result)
 	for _, spec := range result.executedSpecs {
 		r.executed = append(r.executed, spec)
 	}
+	if r.stream != nil {
+		r.stream.executed[result.name] = append(r.stream.executed[result.name], result.executedSpecs...)
+	}
+	if r.failFast && len(result.failedSpecs()) > 0 {
+		r.stopped = true
+	}
+	if r.stopped {
+		return
+	}
+	tasks := make([]*scheduledTask, 0, len(result.postponedSpecs)+1)
 	for _, spec := range result.postponedSpecs {
 		task := newScheduledTask(result.name, result.closure, newExplicitContext(spec.path))
-		r.scheduled = append(r.scheduled, task)
+		task.options = result.options
+		task.target = spec
+		tasks = append(tasks, task)
+	}
+	if result.resumed != nil {
+		task := newScheduledTask(result.name, result.closure, result.resumed)
+		task.options = result.options
+		tasks = append(tasks, task)
+	}
+	if r.isRandomOrder() {
+		r.shuffle(tasks, result.name)
+	}
+	r.scheduled = append(r.scheduled, tasks...)
+	r.unfinished[result.name] += len(tasks)
+}
+
+// When there are focused specs, the specs which are not in focus will be
+// skipped when the results are collected. They are still executed, because
+// their children might be focused, which is known only after executing them.
+func (r *Runner) detectFocusedSpecs(result *taskResult) {
+	for _, spec := range result.executedSpecs {
+		r.focusMode = r.focusMode || spec.focused
+	}
+	for _, spec := range result.postponedSpecs {
+		r.focusMode = r.focusMode || spec.focused
 	}
 }
 
@@ -119,26 +433,214 @@ func (r *Runner) Results() *ResultCollector {
 	for _, spec := range r.executed {
 		results.Update(spec)
 	}
+	if r.filter != nil {
+		results.skipFilteredOut()
+	}
+	if r.focusMode {
+		results.skipNotFocused()
+	}
+	results.stopped = r.stopped
 	return results
 }
 
+func (r *Runner) scheduledRootNames() []string {
+	unique := make(map[string]bool)
+	names := []string{}
+	for _, task := range r.scheduled {
+		if !unique[task.name] {
+			unique[task.name] = true
+			names = append(names, task.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// The root specs are reported in order, so a root spec which has finished
+// waits until the root specs before it have also finished.
+func (r *Runner) reportFinishedRoots() {
+	if r.stream == nil {
+		return
+	}
+	for len(r.stream.unreported) > 0 && r.unfinished[r.stream.unreported[0]] == 0 {
+		r.reportRoot(r.stream.unreported[0])
+		r.stream.unreported = r.stream.unreported[1:]
+	}
+}
+
+// The root specs which did not finish, because failing fast stopped the
+// execution, are reported at the end together with the root specs after them.
+func (r *Runner) finishReport() {
+	if r.stream == nil {
+		return
+	}
+	for _, name := range r.stream.unreported {
+		r.reportRoot(name)
+	}
+	r.stream.unreported = nil
+	r.stream.results.focused = r.focusMode
+	r.stream.results.stopped = r.stopped
+	r.stream.reporter.SuiteFinished(r.stream.results.stats(r.stream.duration))
+}
+
+func (r *Runner) reportRoot(name string) {
+	results := r.stream.results
+	for _, spec := range r.stream.executed[name] {
+		results.Update(spec)
+	}
+	delete(r.stream.executed, name)
+	root, ok := results.rootsByName[name]
+	if !ok {
+		return
+	}
+	if r.filter != nil {
+		root.skipFilteredOutChildren()
+	}
+	if r.focusMode {
+		root.skipNotFocused(false)
+	}
+	r.stream.duration += results.reportRoot(root, r.stream.reporter)
+}
+
+// Results which are reported while the specs are being executed.
+type streamingReport struct {
+	reporter   Reporter
+	results    *ResultCollector
+	executed   map[string][]*specRun
+	unreported []string
+	duration   time.Duration
+}
+
+func newStreamingReport(reporter Reporter) *streamingReport {
+	results := newResultCollector()
+	results.resetSpecCount()
+	return &streamingReport{reporter, results, make(map[string][]*specRun), []string{}, 0}
+}
+
 // Scheduled spec execution.
 type scheduledTask struct {
 	name    string
 	closure specRoot
 	context *taskContext
+	options specOptions
+	target  *specRun
 }
 
 type specRoot func(Context)
 
 func newScheduledTask(name string, closure specRoot, context *taskContext) *scheduledTask {
-	return &scheduledTask{name, closure, context}
+	return &scheduledTask{name, closure, context, specOptions{}, nil}
 }
 
 // Results of a spec execution.
 type taskResult struct {
 	name           string
 	closure        specRoot
+	options        specOptions
 	executedSpecs  []*specRun
 	postponedSpecs []*specRun
+	resumed        *taskContext // continues after a spec which timed out
+}
+
+func (result *taskResult) failedSpecs() []*specRun {
+	failed := []*specRun{}
+	for _, spec := range result.executedSpecs {
+		if spec.errors.Len() > 0 {
+			failed = append(failed, spec)
+		}
+	}
+	return failed
+}
+
+// BeforeAll and AfterAll hooks, which are executed only once even though
+// the spec which registers them is executed many times. The hooks are
+// identified by the spec which registers them and the order of registration.
+type onceHooks struct {
+	mutex     sync.Mutex
+	beforeAll map[string]*onceHook
+	afterAll  map[string]*onceHook
+	order     []*onceHook
+}
+
+type onceHook struct {
+	once      sync.Once
+	spec      *specRun
+	closure   func()
+	exception *exception
+}
+
+func newOnceHooks() *onceHooks {
+	return &onceHooks{
+		beforeAll: make(map[string]*onceHook),
+		afterAll:  make(map[string]*onceHook),
+		order:     []*onceHook{},
+	}
+}
+
+// If the hook is already being executed in another goroutine, waits until
+// it has finished. Returns the panic of the hook, also when the hook was
+// executed earlier.
+func (this *onceHooks) runBeforeAll(key string, closure func()) *exception {
+	this.mutex.Lock()
+	hook, ok := this.beforeAll[key]
+	if !ok {
+		hook = &onceHook{closure: closure}
+		this.beforeAll[key] = hook
+	}
+	this.mutex.Unlock()
+
+	hook.once.Do(func() {
+		hook.exception = recoverOnPanic(hook.closure)
+	})
+	return hook.exception
+}
+
+func (this *onceHooks) registerAfterAll(key string, spec *specRun, closure func()) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if _, ok := this.afterAll[key]; !ok {
+		hook := &onceHook{spec: spec, closure: closure}
+		this.afterAll[key] = hook
+		this.order = append(this.order, hook)
+	}
+}
+
+// The hooks are executed in the reverse order of their registration, so that
+// the hooks of nested specs are executed before the hooks of their parents.
+// A panic fails the spec which registered the hook.
+func (this *onceHooks) runAfterAllHooks() {
+	this.mutex.Lock()
+	order := this.order
+	this.order = []*onceHook{}
+	this.mutex.Unlock()
+
+	runInReverseOrder(order)
+}
+
+// Executes the hooks which were registered by the root spec or its children,
+// when all of them have been executed.
+func (this *onceHooks) runAfterAllHooksOf(rootName string) {
+	this.mutex.Lock()
+	order := []*onceHook{}
+	remaining := []*onceHook{}
+	for _, hook := range this.order {
+		if hook.spec.rootParent().name == rootName {
+			order = append(order, hook)
+		} else {
+			remaining = append(remaining, hook)
+		}
+	}
+	this.order = remaining
+	this.mutex.Unlock()
+
+	runInReverseOrder(order)
+}
+
+func runInReverseOrder(order []*onceHook) {
+	for i := len(order) - 1; i >= 0; i-- {
+		hook := order[i]
+		if e := recoverOnPanic(hook.closure); e != nil {
+			hook.spec.AddError(e.ToError())
+		}
+	}
 }
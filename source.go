@@ -0,0 +1,61 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The lines of the source files which have been read, so that every file is
+// read only once even if it has many failures. Files which could not be read
+// are cached as nil.
+var sourceFiles = struct {
+	sync.Mutex
+	lines map[string][]string
+}{lines: make(map[string][]string)}
+
+func sourceLines(file string) []string {
+	sourceFiles.Lock()
+	defer sourceFiles.Unlock()
+	lines, ok := sourceFiles.lines[file]
+	if !ok {
+		if content, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		sourceFiles.lines[file] = lines
+	}
+	return lines
+}
+
+// Returns the line of the source file and `context` lines before and after it,
+// prefixed with their line numbers, and the line itself highlighted with ">>".
+// Returns an empty string if the file cannot be read or it has no such line.
+func readSourceContext(file string, line int, context int) string {
+	lines := sourceLines(file)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	first := line - context
+	if first < 1 {
+		first = 1
+	}
+	last := line + context
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(fmt.Sprint(last))
+	s := ""
+	for i := first; i <= last; i++ {
+		marker := "  "
+		if i == line {
+			marker = ">>"
+		}
+		s += fmt.Sprintf("%v %*d: %v\n", marker, width, i, strings.TrimRight(lines[i-1], "\r"))
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"os"
+	"path/filepath"
+)
+
+func SourceSpec(c nanospec.Context) {
+	dir, err := os.MkdirTemp("", "gospec")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "some_test.go")
+	os.WriteFile(file, []byte("line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nline 10\n"), 0644)
+
+	c.Specify("The line is highlighted among the lines around it", func() {
+		c.Expect(readSourceContext(file, 5, 1)).Equals("" +
+			"   4: line 4\n" +
+			">> 5: line 5\n" +
+			"   6: line 6\n")
+	})
+	c.Specify("The line numbers are aligned", func() {
+		c.Expect(readSourceContext(file, 9, 1)).Equals("" +
+			"    8: line 8\n" +
+			">>  9: line 9\n" +
+			"   10: line 10\n")
+	})
+	c.Specify("The context is limited to the start and end of the file", func() {
+		c.Expect(readSourceContext(file, 1, 2)).Equals("" +
+			">> 1: line 1\n" +
+			"   2: line 2\n" +
+			"   3: line 3\n")
+		c.Expect(readSourceContext(file, 11, 1)).Equals("" +
+			"   10: line 10\n" +
+			">> 11: \n")
+	})
+	c.Specify("Nothing is returned when the file cannot be read", func() {
+		c.Expect(readSourceContext(filepath.Join(dir, "no_such_file.go"), 1, 1)).Equals("")
+	})
+	c.Specify("Nothing is returned when the file has no such line", func() {
+		c.Expect(readSourceContext(file, 0, 1)).Equals("")
+		c.Expect(readSourceContext(file, 100, 1)).Equals("")
+	})
+	c.Specify("The file contents are cached", func() {
+		readSourceContext(file, 1, 0)
+		os.Remove(file)
+		c.Expect(readSourceContext(file, 1, 0)).Equals(">> 1: line 1\n")
+	})
+}
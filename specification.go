@@ -7,6 +7,8 @@ package gospec
 import (
 	"container/list"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // Represents a spec in a tree of specs.
@@ -19,6 +21,19 @@ type specRun struct {
 	targetPath       path
 	errors           *list.List
 	hasFatalErrors   bool
+	duration         time.Duration
+	skipReason       string
+	focused          bool
+	pending          bool
+	matchesFilter    bool
+	timeout          time.Duration
+	beforeEach       []func()
+	afterEach        []func()
+	hookCount        int
+	cleanups         []func()
+	childStarted     time.Time
+	childrenDuration time.Duration
+	mutex            sync.Mutex
 }
 
 func newSpecRun(name string, closure func(), parent *specRun, targetPath path) *specRun {
@@ -28,19 +43,183 @@ func newSpecRun(name string, closure func(), parent *specRun, targetPath path) *
 		path = parent.path.append(currentIndex)
 		parent.numberOfChildren++
 	}
-	return &specRun{name, closure, parent, 0, path, targetPath, list.New(), false}
+	return &specRun{
+		name:       name,
+		closure:    closure,
+		parent:     parent,
+		path:       path,
+		targetPath: targetPath,
+		errors:     list.New(),
+	}
 }
 
 func (spec *specRun) isOnTargetPath() bool { return spec.path.isOn(spec.targetPath) }
 func (spec *specRun) isUnseen() bool       { return spec.path.isBeyond(spec.targetPath) }
 func (spec *specRun) isFirstChild() bool   { return spec.path.lastIndex() == 0 }
 
-func (spec *specRun) execute() {
-	exception := recoverOnPanic(spec.closure)
-	if exception != nil {
+// Called when the spec times out, before waiting for the spec to return.
+type timeoutHandler func(spec *specRun, timeoutError *Error)
+
+func (spec *specRun) execute(onTimeout timeoutHandler) {
+	start := time.Now()
+	if spec.parent != nil {
+		spec.parent.childStarts(start)
+	}
+	if exception := spec.runBeforeEachHooks(); exception != nil {
+		spec.handleException(exception)
+	} else if exception := spec.executeWithTimeout(onTimeout); exception != nil {
 		spec.fixupStackTraceForRootSpec(exception)
-		spec.AddFatalError(exception.ToError())
+		spec.handleException(exception)
+	}
+	spec.runCleanups()
+	spec.runAfterEachHooks()
+	end := time.Now()
+	spec.setDuration(end.Sub(start))
+	if spec.parent != nil {
+		spec.parent.childEnds(end)
+	}
+}
+
+func (spec *specRun) deferCleanup(cleanup func()) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	spec.cleanups = append(spec.cleanups, cleanup)
+}
+
+// The cleanups are executed in the reverse order of their registration.
+// A panic fails the spec, but the rest of the cleanups are still executed.
+func (spec *specRun) runCleanups() {
+	spec.mutex.Lock()
+	cleanups := spec.cleanups
+	spec.cleanups = nil
+	spec.mutex.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if e := recoverOnPanic(cleanups[i]); e != nil {
+			spec.AddError(e.ToError())
+		}
+	}
+}
+
+func (spec *specRun) handleException(e *exception) {
+	if skipped, ok := e.Cause.(specSkipped); ok {
+		spec.skip(skipped.reason)
+	} else {
+		spec.AddFatalError(e.ToError())
+	}
+}
+
+// The hooks of the parent are executed around every child. If a BeforeEach
+// hook panics, the rest of the hooks and the spec will not be executed.
+func (spec *specRun) runBeforeEachHooks() *exception {
+	if spec.parent == nil {
+		return nil
+	}
+	for _, hook := range spec.parent.beforeEach {
+		if e := recoverOnPanic(hook); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// The AfterEach hooks are executed in reverse order, even if the spec failed.
+func (spec *specRun) runAfterEachHooks() {
+	if spec.parent == nil {
+		return
+	}
+	hooks := spec.parent.afterEach
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if e := recoverOnPanic(hooks[i]); e != nil {
+			spec.AddError(e.ToError())
+		}
+	}
+}
+
+// The closure is executed in a new goroutine, so that the spec can fail
+// when it times out. The goroutine cannot be stopped, so after the timeout
+// this waits until the closure returns, before the cleanups and hooks
+// are executed. The time spent executing the children of the spec does not
+// count, because the children have their own timeouts.
+func (spec *specRun) executeWithTimeout(onTimeout timeoutHandler) *exception {
+	if spec.timeout <= 0 {
+		return recoverOnPanic(spec.closure)
+	}
+	start := time.Now()
+	done := make(chan *exception, 1)
+	go func() {
+		done <- recoverOnPanic(spec.closure)
+	}()
+	timer := time.NewTimer(spec.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case e := <-done:
+			return e
+		case now := <-timer.C:
+			children, executingChild := spec.timeInChildren(now)
+			elapsed := now.Sub(start) - children
+			switch {
+			case elapsed >= spec.timeout:
+				message := fmt.Sprintf("Spec '%v' timed out after %v.", spec.name, spec.timeout)
+				spec.setDuration(now.Sub(start))
+				onTimeout(spec, newError(OtherError, message, "", []*Location{}))
+				return <-done
+			case executingChild:
+				timer.Reset(spec.timeout)
+			default:
+				timer.Reset(spec.timeout - elapsed)
+			}
+		}
+	}
+}
+
+func (spec *specRun) childStarts(now time.Time) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	spec.childStarted = now
+}
+
+func (spec *specRun) childEnds(now time.Time) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	spec.childrenDuration += now.Sub(spec.childStarted)
+	spec.childStarted = time.Time{}
+}
+
+func (spec *specRun) timeInChildren(now time.Time) (duration time.Duration, executingChild bool) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	duration = spec.childrenDuration
+	if !spec.childStarted.IsZero() {
+		duration += now.Sub(spec.childStarted)
+		executingChild = true
+	}
+	return duration, executingChild
+}
+
+func (spec *specRun) setDuration(duration time.Duration) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	spec.duration = duration
+}
+
+// Panic value which stops executing a skipped spec.
+type specSkipped struct {
+	reason string
+}
+
+func exceptionCause(e *exception) interface{} {
+	if e == nil {
+		return nil
 	}
+	return e.Cause
+}
+
+func (spec *specRun) skip(reason string) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	spec.skipReason = reason
 }
 
 func (spec *specRun) fixupStackTraceForRootSpec(e *exception) {
@@ -53,15 +232,50 @@ func (spec *specRun) fixupStackTraceForRootSpec(e *exception) {
 	}
 }
 
+// The errors may be added from multiple goroutines, if the spec
+// starts goroutines which make expectations.
 func (spec *specRun) AddError(error *Error) {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
 	spec.errors.PushBack(error)
 }
 
 func (spec *specRun) AddFatalError(error *Error) {
-	spec.AddError(error)
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	spec.errors.PushBack(error)
 	spec.hasFatalErrors = true
 }
 
+func (spec *specRun) hasFailedFatally() bool {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	return spec.hasFatalErrors
+}
+
+// Copies the results of the spec, so that they will not change even if
+// the spec is still being executed in another goroutine.
+func (spec *specRun) snapshot() *specRun {
+	spec.mutex.Lock()
+	defer spec.mutex.Unlock()
+	errors := list.New()
+	errors.PushBackList(spec.errors)
+	return &specRun{
+		name:           spec.name,
+		parent:         spec.parent,
+		path:           spec.path,
+		targetPath:     spec.targetPath,
+		errors:         errors,
+		hasFatalErrors: spec.hasFatalErrors,
+		duration:       spec.duration,
+		skipReason:     spec.skipReason,
+		focused:        spec.focused,
+		pending:        spec.pending,
+		matchesFilter:  spec.matchesFilter,
+		timeout:        spec.timeout,
+	}
+}
+
 func (spec *specRun) rootParent() *specRun {
 	root := spec
 	for root.parent != nil {
@@ -70,6 +284,20 @@ func (spec *specRun) rootParent() *specRun {
 	return root
 }
 
+// A spec is in focus if it or one of its parents is focused.
+func (spec *specRun) isInFocus() bool {
+	return spec.focused || (spec.parent != nil && spec.parent.isInFocus())
+}
+
+// Names of the spec and its parents, starting from the root spec,
+// separated by spaces.
+func (spec *specRun) fullName() string {
+	if spec.parent == nil {
+		return spec.name
+	}
+	return spec.parent.fullName() + " " + spec.name
+}
+
 func (spec *specRun) String() string {
 	return fmt.Sprintf("%T{%v @ %v}", spec, spec.name, spec.path)
 }
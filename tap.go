@@ -0,0 +1,81 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// TAPReporter writes the spec results in the Test Anything Protocol
+// (version 13) format. Every spec is reported as its own test point, and the
+// failures are described in YAML diagnostic blocks. Because the plan line
+// must contain the number of specs, the output is buffered and written when
+// the suite has finished.
+type TAPReporter struct {
+	out    io.Writer
+	buffer *bytes.Buffer
+	path   []string
+	count  int
+}
+
+func NewTAPReporter(out io.Writer) *TAPReporter {
+	return &TAPReporter{
+		out:    out,
+		buffer: new(bytes.Buffer),
+		path:   []string{},
+		count:  0,
+	}
+}
+
+func (this *TAPReporter) SpecStarted(nestingLevel int, name string) {
+	this.path = append(this.path[:nestingLevel], name)
+	this.count++
+	if nestingLevel == 0 {
+		fmt.Fprintf(this.buffer, "# %v\n", name)
+	}
+}
+
+func (this *TAPReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	fmt.Fprintf(this.buffer, "ok %v - %v\n", this.count, specPathName(this.path))
+}
+
+func (this *TAPReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	fmt.Fprintf(this.buffer, "not ok %v - %v\n", this.count, specPathName(this.path))
+	fmt.Fprint(this.buffer, "  ---\n")
+	fmt.Fprint(this.buffer, "  errors:\n")
+	for _, error := range errors {
+		fmt.Fprintf(this.buffer, "    - type: %v\n", errorTypeName(error.Type))
+		fmt.Fprintf(this.buffer, "      message: %v\n", strconv.Quote(error.Message))
+		if error.Type != OtherError {
+			fmt.Fprintf(this.buffer, "      got: %v\n", strconv.Quote(error.Actual))
+		}
+		if len(error.StackTrace) > 0 {
+			fmt.Fprint(this.buffer, "      at:\n")
+			for _, loc := range error.StackTrace {
+				fmt.Fprintf(this.buffer, "        - %v\n", strconv.Quote(loc.String()))
+			}
+		}
+	}
+	fmt.Fprint(this.buffer, "  ...\n")
+}
+
+func (this *TAPReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	fmt.Fprintf(this.buffer, "ok %v - %v # SKIP %v\n", this.count, specPathName(this.path), reason)
+}
+
+func (this *TAPReporter) SpecPending(nestingLevel int, name string) {
+	fmt.Fprintf(this.buffer, "not ok %v - %v # TODO pending\n", this.count, specPathName(this.path))
+}
+
+func (this *TAPReporter) SuiteFinished(stats Stats) {
+	fmt.Fprint(this.out, "TAP version 13\n")
+	fmt.Fprintf(this.out, "1..%v\n", this.count)
+	this.buffer.WriteTo(this.out)
+	fmt.Fprintf(this.out, "# %v specs, %v failures\n", stats.TotalCount(), stats.FailCount)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bytes"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+)
+
+func TAPSpec(c nanospec.Context) {
+	trim := strings.TrimSpace
+	out := new(bytes.Buffer)
+	reporter := NewTAPReporter(out)
+
+	c.Specify("Passing specs are reported as ok test points", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reportSpec(reporter, 1, "Child A", noErrors, 0)
+		reportSpec(reporter, 2, "Child AA", noErrors, 0)
+		reportSpec(reporter, 1, "Child B", noErrors, 0)
+		reporter.SuiteFinished(Stats{PassCount: 4, FailCount: 0})
+		c.Expect(trim(out.String())).Equals(trim(`
+TAP version 13
+1..4
+# RootSpec
+ok 1 - RootSpec
+ok 2 - Child A
+ok 3 - Child A / Child AA
+ok 4 - Child B
+# 4 specs, 0 failures
+`))
+	})
+
+	c.Specify("Failing specs are reported with YAML diagnostics", func() {
+		location := &Location{"gospec.SomeSpec", "/path/to/some_test.go", 42}
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		reportSpec(reporter, 1, "Failing", []*Error{
+			newError(ExpectFailed, "equals “20”", "10", []*Location{location}),
+			newError(OtherError, "panic: \"boom\"\nagain", "", []*Location{}),
+		}, 0)
+		reporter.SuiteFinished(Stats{PassCount: 1, FailCount: 1})
+		c.Expect(trim(out.String())).Equals(trim(`
+TAP version 13
+1..2
+# RootSpec
+ok 1 - RootSpec
+not ok 2 - Failing
+  ---
+  errors:
+    - type: expectation
+      message: "equals “20”"
+      got: "10"
+      at:
+        - "some_test.go:42"
+    - type: error
+      message: "panic: \"boom\"\nagain"
+  ...
+# 2 specs, 1 failures
+`))
+	})
+
+	c.Specify("Every root spec starts with a comment", func() {
+		reportSpec(reporter, 0, "RootSpec1", noErrors, 0)
+		reportSpec(reporter, 0, "RootSpec2", noErrors, 0)
+		reporter.SuiteFinished(Stats{PassCount: 2, FailCount: 0})
+		lines := strings.Split(trim(out.String()), "\n")
+		c.Expect(lines[2]).Equals("# RootSpec1")
+		c.Expect(lines[4]).Equals("# RootSpec2")
+	})
+
+	c.Specify("Nothing is written before the suite has finished", func() {
+		reportSpec(reporter, 0, "RootSpec", noErrors, 0)
+		c.Expect(out.Len()).Equals(0)
+	})
+
+	c.Specify("Skipped specs are reported with a SKIP directive", func() {
+		reporter.SpecStarted(0, "RootSpec")
+		reporter.SpecSkipped(0, "RootSpec", "not supported")
+		reporter.SuiteFinished(Stats{SkipCount: 1})
+		c.Expect(strings.Contains(out.String(), "ok 1 - RootSpec # SKIP not supported\n")).IsTrue()
+	})
+
+	c.Specify("Pending specs are reported with a TODO directive", func() {
+		reporter.SpecStarted(0, "RootSpec")
+		reporter.SpecPending(0, "RootSpec")
+		reporter.SuiteFinished(Stats{PendingCount: 1})
+		c.Expect(strings.Contains(out.String(), "not ok 1 - RootSpec # TODO pending\n")).IsTrue()
+	})
+}
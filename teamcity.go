@@ -0,0 +1,100 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// TeamCityReporter writes the spec results as TeamCity service messages.
+// Every root spec is reported as a test suite, and every spec as a test.
+type TeamCityReporter struct {
+	out   io.Writer
+	path  []string
+	suite string
+}
+
+func NewTeamCityReporter(out io.Writer) *TeamCityReporter {
+	return &TeamCityReporter{out, []string{}, ""}
+}
+
+// Tells whether the process is being run by TeamCity.
+func isTeamCity() bool {
+	return os.Getenv("TEAMCITY_VERSION") != ""
+}
+
+func (this *TeamCityReporter) SpecStarted(nestingLevel int, name string) {
+	this.path = append(this.path[:nestingLevel], name)
+	if nestingLevel == 0 {
+		this.finishSuite()
+		this.suite = name
+		this.message("testSuiteStarted", "name", this.suite)
+	}
+	this.message("testStarted", "name", specPathName(this.path))
+}
+
+func (this *TeamCityReporter) SpecPassed(nestingLevel int, name string, duration time.Duration) {
+	this.testFinished(duration)
+}
+
+func (this *TeamCityReporter) SpecFailed(nestingLevel int, name string, errors []*Error, duration time.Duration) {
+	for _, error := range errors {
+		details := ""
+		for _, loc := range error.StackTrace {
+			details += fmt.Sprintf("at %v\n", loc)
+		}
+		message := strings.TrimSuffix(formatErrorMessage(error), "\n")
+		this.message("testFailed", "name", specPathName(this.path), "message", message, "details", details)
+	}
+	this.testFinished(duration)
+}
+
+func (this *TeamCityReporter) SpecSkipped(nestingLevel int, name string, reason string) {
+	this.message("testIgnored", "name", specPathName(this.path), "message", reason)
+	this.testFinished(0)
+}
+
+func (this *TeamCityReporter) SpecPending(nestingLevel int, name string) {
+	this.SpecSkipped(nestingLevel, name, "pending")
+}
+
+func (this *TeamCityReporter) SuiteFinished(stats Stats) {
+	this.finishSuite()
+}
+
+func (this *TeamCityReporter) testFinished(duration time.Duration) {
+	this.message("testFinished", "name", specPathName(this.path), "duration", fmt.Sprint(duration.Nanoseconds()/int64(time.Millisecond)))
+}
+
+func (this *TeamCityReporter) finishSuite() {
+	if this.suite != "" {
+		this.message("testSuiteFinished", "name", this.suite)
+		this.suite = ""
+	}
+}
+
+// Writes a service message with the given attribute names and values.
+func (this *TeamCityReporter) message(messageName string, attributes ...string) {
+	s := "##teamcity[" + messageName
+	for i := 0; i+1 < len(attributes); i += 2 {
+		s += fmt.Sprintf(" %v='%v'", attributes[i], teamCityEscape(attributes[i+1]))
+	}
+	fmt.Fprintln(this.out, s+"]")
+}
+
+func teamCityEscape(s string) string {
+	return strings.NewReplacer(
+		"|", "||",
+		"'", "|'",
+		"\n", "|n",
+		"\r", "|r",
+		"[", "|[",
+		"]", "|]",
+	).Replace(s)
+}
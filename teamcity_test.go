@@ -0,0 +1,72 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"bytes"
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+	"strings"
+	"time"
+)
+
+func TeamCitySpec(c nanospec.Context) {
+	trim := strings.TrimSpace
+	out := new(bytes.Buffer)
+	reporter := NewTeamCityReporter(out)
+
+	c.Specify("Every root spec is a test suite and every spec is a test", func() {
+		reportSpec(reporter, 0, "RootSpec1", noErrors, 5*time.Millisecond)
+		reportSpec(reporter, 1, "Child", noErrors, 2*time.Millisecond)
+		reportSpec(reporter, 0, "RootSpec2", noErrors, 0)
+		reporter.SuiteFinished(Stats{PassCount: 3, FailCount: 0})
+		c.Expect(trim(out.String())).Equals(trim(`
+##teamcity[testSuiteStarted name='RootSpec1']
+##teamcity[testStarted name='RootSpec1']
+##teamcity[testFinished name='RootSpec1' duration='5']
+##teamcity[testStarted name='Child']
+##teamcity[testFinished name='Child' duration='2']
+##teamcity[testSuiteFinished name='RootSpec1']
+##teamcity[testSuiteStarted name='RootSpec2']
+##teamcity[testStarted name='RootSpec2']
+##teamcity[testFinished name='RootSpec2' duration='0']
+##teamcity[testSuiteFinished name='RootSpec2']
+`))
+	})
+
+	c.Specify("Failures are reported between the start and finish of a test", func() {
+		location := &Location{"gospec.SomeSpec", "/path/to/some_test.go", 42}
+		reportSpec(reporter, 0, "RootSpec", []*Error{newError(ExpectFailed, "equals “20”", "10", []*Location{location})}, 0)
+		reporter.SuiteFinished(Stats{PassCount: 0, FailCount: 1})
+		c.Expect(trim(out.String())).Equals(trim(`
+##teamcity[testSuiteStarted name='RootSpec']
+##teamcity[testStarted name='RootSpec']
+##teamcity[testFailed name='RootSpec' message='*** Expected: equals “20”|n         got: “10”' details='at some_test.go:42|n']
+##teamcity[testFinished name='RootSpec' duration='0']
+##teamcity[testSuiteFinished name='RootSpec']
+`))
+	})
+
+	c.Specify("Special characters are escaped", func() {
+		c.Expect(teamCityEscape("it's [a|b]\r\n")).Equals("it|'s |[a||b|]|r|n")
+
+		reportSpec(reporter, 0, "Spec's [name]", noErrors, 0)
+		c.Expect(strings.Contains(out.String(), "name='Spec|'s |[name|]'")).IsTrue()
+	})
+
+	c.Specify("TeamCity is detected from the environment", func() {
+		withEnv("TEAMCITY_VERSION", "2023.05", func() {
+			c.Expect(isTeamCity()).IsTrue()
+		})
+		withEnv("TEAMCITY_VERSION", "", func() {
+			c.Expect(isTeamCity()).IsFalse()
+		})
+	})
+
+	c.Specify("Skipped specs are reported as ignored tests", func() {
+		reporter.SpecStarted(0, "RootSpec")
+		reporter.SpecSkipped(0, "RootSpec", "not supported")
+		c.Expect(strings.Contains(out.String(), "##teamcity[testIgnored name='RootSpec' message='not supported']")).IsTrue()
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"reflect"
+)
+
+// TypedMatcher is the same as Matcher, but the actual and expected values
+// have a static type, so that mismatched types are found by the compiler
+// instead of being reported as errors when the spec is executed. Use Typed
+// to convert a TypedMatcher into a Matcher, for example:
+//    c.Expect(len(items), Typed(TypedEquals[int]), 3)
+type TypedMatcher[T any] func(actual T, expected T) (match bool, pos Message, neg Message, err error)
+
+// Converts a TypedMatcher into a Matcher, so that it can be used in
+// expectations. Gives an error if the actual or expected value is not of
+// type T. A nil actual value is accepted only if T can be nil, but a missing
+// expected value is treated as the zero value of T.
+func Typed[T any](matcher TypedMatcher[T]) Matcher {
+	return func(actual_ interface{}, expected_ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := actualToTyped[T](actual_)
+		if err != nil {
+			return
+		}
+		expected, err := toTyped[T](expected_)
+		if err != nil {
+			return
+		}
+		return matcher(actual, expected)
+	}
+}
+
+// Converts a Matcher into a TypedMatcher, so that the existing matchers can
+// be used where a TypedMatcher is needed.
+func Untyped[T any](matcher Matcher) TypedMatcher[T] {
+	return func(actual T, expected T) (match bool, pos Message, neg Message, err error) {
+		return matcher(actual, expected)
+	}
+}
+
+// Unlike a missing expected value, a nil actual value is not
+// the zero value of T, unless T is a type which can be nil.
+func actualToTyped[T any](value interface{}) (result T, err error) {
+	if value == nil && !canBeNil(reflect.TypeOf(&result).Elem()) {
+		err = Errorf("type error: expected a value of type “%T”, but was “%v”", result, value)
+		return
+	}
+	return toTyped[T](value)
+}
+
+func canBeNil(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+func toTyped[T any](value interface{}) (result T, err error) {
+	if value == nil {
+		return
+	}
+	result, ok := value.(T)
+	if !ok {
+		err = Errorf("type error: expected a value of type “%T”, but was “%v” of type “%T”", result, value, value)
+	}
+	return
+}
+
+// Float is a constraint for the floating-point types.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Same as Equals, but for values of the same comparable type.
+func TypedEquals[T comparable](actual T, expected T) (match bool, pos Message, neg Message, err error) {
+	return Equals(actual, expected)
+}
+
+// Same as ContainsAll, but for slices of the same element type.
+func TypedContainsAll[T comparable](actual []T, expected []T) (match bool, pos Message, neg Message, err error) {
+	return ContainsAll(actual, expected)
+}
+
+// Same as IsWithin, but for floating-point values of the same type.
+func TypedIsWithin[T Float](delta T) TypedMatcher[T] {
+	matcher := IsWithin(float64(delta))
+	return func(actual T, expected T) (match bool, pos Message, neg Message, err error) {
+		return matcher(float64(actual), float64(expected))
+	}
+}
+
+// Same as Passes, but the predicate takes a value of type T. Gives an error
+// if the actual value is not of type T.
+func PassesFunc[T any](predicate func(actual T) bool) Matcher {
+	return func(actual_ interface{}, _ interface{}) (match bool, pos Message, neg Message, err error) {
+		actual, err := actualToTyped[T](actual_)
+		if err != nil {
+			return
+		}
+		match = predicate(actual)
+		pos = newMessage(actual_, errorf0("passes the predicate"))
+		neg = newMessage(actual_, errorf0("does NOT pass the predicate"))
+		return
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright © 2009-2011 Esko Luontola <www.orfjackal.net>
+// This software is released under the Apache License 2.0.
+// The license text is at http://www.apache.org/licenses/LICENSE-2.0
+
+package gospec
+
+import (
+	"github.com/orfjackal/nanospec.go/src/nanospec"
+)
+
+type typedDummyFloat float64
+
+func TypedMatchersSpec(c nanospec.Context) {
+
+	c.Specify("Typed matchers can be called with statically typed values", func() {
+		// These would not compile if the types did not match
+		match, _, _, _ := TypedEquals(1, 1)
+		c.Expect(match).IsTrue()
+		match, _, _, _ = TypedEquals("a", "b")
+		c.Expect(match).IsFalse()
+		match, _, _, _ = TypedContainsAll([]string{"a", "b", "c"}, []string{"c", "a"})
+		c.Expect(match).IsTrue()
+		match, _, _, _ = TypedIsWithin(typedDummyFloat(0.1))(1.0, 1.05)
+		c.Expect(match).IsTrue()
+	})
+
+	c.Specify("Typed matchers have the same messages as the untyped matchers", func() {
+		_, pos, neg, _ := TypedEquals(1, 2)
+		c.Expect(pos.Expectation()).Equals("equals “2”")
+		c.Expect(neg.Expectation()).Equals("does NOT equal “2”")
+		_, pos, _, _ = TypedIsWithin(float32(0.5))(1, 2)
+		c.Expect(pos.Expectation()).Equals("is within 2 ± 0.5")
+	})
+
+	c.Specify("Typed converts a TypedMatcher into a Matcher", func() {
+		c.Expect(E(42, Typed(TypedEquals[int]), 42)).Matches(Succeeds)
+		c.Expect(E(42, Typed(TypedEquals[int]), 43)).Matches(FailsWithMessage(
+			"equals “43”",
+			"does NOT equal “43”"))
+		c.Expect(E(42, Not(Typed(TypedEquals[int])), 43)).Matches(Succeeds)
+		c.Expect(E([]int{1, 2}, Typed(TypedContainsAll[int]), []int{2})).Matches(Succeeds)
+	})
+	c.Specify("Typed gives an error when the values are of a wrong type", func() {
+		c.Expect(E("42", Typed(TypedEquals[int]), 42)).Matches(GivesError(
+			"type error: expected a value of type “int”, but was “42” of type “string”"))
+		c.Expect(E(42, Typed(TypedEquals[int]), int64(42))).Matches(GivesError(
+			"type error: expected a value of type “int”, but was “42” of type “int64”"))
+	})
+	c.Specify("Typed gives an error when the actual value is nil and T cannot be nil", func() {
+		c.Expect(E(nil, Typed(TypedEquals[int]), 0)).Matches(GivesError(
+			"type error: expected a value of type “int”, but was “<nil>”"))
+		c.Expect(E(nil, PassesFunc(func(actual int) bool { return true }))).Matches(GivesError(
+			"type error: expected a value of type “int”, but was “<nil>”"))
+	})
+	c.Specify("Typed accepts a nil actual value when T can be nil", func() {
+		c.Expect(E(nil, Typed(TypedEquals[*int]), (*int)(nil))).Matches(Succeeds)
+		c.Expect(E(nil, Typed(TypedEquals[error]))).Matches(Succeeds)
+	})
+	c.Specify("Typed uses the zero value when there is no expected value", func() {
+		c.Expect(E(0, Typed(TypedEquals[int]))).Matches(Succeeds)
+		c.Expect(E(1, Typed(TypedEquals[int]))).Matches(Fails)
+	})
+
+	c.Specify("Untyped converts a Matcher into a TypedMatcher", func() {
+		var matcher TypedMatcher[string] = Untyped[string](EqualsFold)
+		match, pos, _, _ := matcher("FOO", "foo")
+		c.Expect(match).IsTrue()
+		c.Expect(pos.Expectation()).Equals("equals “foo” (case-insensitive)")
+	})
+
+	c.Specify("PassesFunc calls a statically typed predicate", func() {
+		isSmall := func(actual int) bool { return actual < 100 }
+		c.Expect(E(42, PassesFunc(isSmall))).Matches(Succeeds)
+		c.Expect(E(420, PassesFunc(isSmall))).Matches(FailsWithMessage(
+			"passes the predicate",
+			"does NOT pass the predicate"))
+		c.Expect(E("42", PassesFunc(isSmall))).Matches(GivesError(
+			"type error: expected a value of type “int”, but was “42” of type “string”"))
+	})
+
+	c.Specify("Typed matchers can be used in expectations", func() {
+		results := runSpec(func(c Context) {
+			c.Expect(3, Typed(TypedEquals[int]), 3)
+			c.Expect(3, Typed(TypedEquals[int]), 4)
+		})
+		c.Expect(results.FailCount()).Equals(1)
+	})
+}